@@ -0,0 +1,121 @@
+package structmapper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/structmapper/structmapper/test/dto"
+	"github.com/structmapper/structmapper/test/proto"
+)
+
+type benchmarkFrom struct {
+	ID     string  `json:"id"`
+	Name   string  `json:"name"`
+	Age    int32   `json:"age"`
+	Weight float64 `json:"weight"`
+	Alive  bool    `json:"alive"`
+	Email  string  `json:"email"`
+	Score  int64   `json:"score"`
+	Rank   int32   `json:"rank"`
+}
+
+type benchmarkTo struct {
+	Id     string  `json:"id"`
+	Name   string  `json:"name"`
+	Age    int64   `json:"age"`
+	Weight float64 `json:"weight"`
+	Alive  bool    `json:"alive"`
+	Email  string  `json:"email"`
+	Score  int64   `json:"score"`
+	Rank   int64   `json:"rank"`
+}
+
+func BenchmarkCopyFlatStruct(b *testing.B) {
+	mapper := New()
+	from := &benchmarkFrom{ID: "12345", Name: "Satoshi Nakamoto", Age: 47, Weight: 12.3, Alive: true, Email: "satoshi@example.com", Score: 123, Rank: 1}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := mapper.From(from).CopyTo(new(benchmarkTo)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCopyDtoToProto(b *testing.B) {
+	mapper := New().Install(ProtobufModule).Install(StringerModule)
+	createdAt := time.Date(2019, 7, 7, 12, 34, 56, 0, time.UTC)
+	from := &dto.User{
+		ID:            "12345",
+		Name:          "Satoshi Nakamoto",
+		Age:           47,
+		Weight:        12.3,
+		Sex:           dto.SexFemale,
+		Alive:         true,
+		BirthDate:     String("1999-11-17"),
+		Num64:         123,
+		OptionalNum:   Int32(123),
+		OptionalNum64: CustonInt64(123),
+		Numbers:       []int64{1, 2, 3},
+		Times:         []time.Time{createdAt, createdAt, createdAt},
+		CreatedAt:     createdAt,
+		ModifiedAt:    createdAt,
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := mapper.From(from).CopyTo(new(proto.User)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCopyProtoToDto(b *testing.B) {
+	mapper := New().Install(ProtobufModule).Install(StringerModule)
+	createdAt := &timestamp.Timestamp{Seconds: 1562502896}
+	from := &proto.User{
+		Id:            "12345",
+		Name:          "Satoshi Nakamoto",
+		Age:           47,
+		Weight:        12.3,
+		Sex:           "Female",
+		Alive:         true,
+		BirthDate:     "1999-11-17",
+		Num64:         123,
+		OptionalNum:   &wrappers.Int64Value{Value: 123},
+		OptionalNum64: &wrappers.Int64Value{Value: 123},
+		Numbers:       []int64{1, 2, 3},
+		Times:         []*timestamp.Timestamp{createdAt, createdAt, createdAt},
+		CreatedAt:     createdAt,
+		ModifiedAt:    createdAt,
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := mapper.From(from).CopyTo(new(dto.User)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCopySlice(b *testing.B) {
+	mapper := New()
+	from := make([]benchmarkFrom, 1000)
+	for i := range from {
+		from[i] = benchmarkFrom{ID: "12345", Name: "Satoshi Nakamoto", Age: 47, Weight: 12.3, Alive: true, Email: "satoshi@example.com", Score: 123, Rank: 1}
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var to []benchmarkTo
+		if err := mapper.From(from).CopyTo(&to); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,1003 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"go/types"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+	"github.com/structmapper/structmapper"
+)
+
+const structmapperPath = "github.com/structmapper/structmapper"
+
+// Packages of protobuf well-known types, imported by their github.com/golang/protobuf aliases as ProtobufModule does
+var aliasPackages = map[string]string{
+	"google.golang.org/protobuf/types/known/timestamppb": "github.com/golang/protobuf/ptypes/timestamp",
+	"google.golang.org/protobuf/types/known/wrapperspb":  "github.com/golang/protobuf/ptypes/wrappers",
+}
+
+type generator struct {
+	// import path of generated package
+	localPath    string
+	transformers []transformer
+	// struct tag keys as Mapper.TagNames, defaults if empty
+	tagNames []string
+
+	// import path -> name
+	imports map[string]string
+	// name -> import path
+	importNames map[string]string
+
+	funcs []*mapFunc
+	// by pairKey
+	funcsByPair map[string]*mapFunc
+	funcNames   map[string]struct{}
+
+	// body of function being generated
+	buf  *bytes.Buffer
+	tmps int
+	errs []string
+}
+
+// mapFunc maps *from to *to with path of source root for errors
+type mapFunc struct {
+	name     string
+	exported []string
+	from     *types.Named
+	to       *types.Named
+	body     []byte
+}
+
+func newGenerator(localPath string, moduleNames []string) (*generator, error) {
+	g := &generator{
+		localPath:   localPath,
+		imports:     make(map[string]string),
+		importNames: make(map[string]string),
+		funcsByPair: make(map[string]*mapFunc),
+		funcNames:   make(map[string]struct{}),
+	}
+
+	for _, name := range moduleNames {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		module, ok := modules[name]
+		if !ok {
+			return nil, errors.Errorf("unknown module %s", name)
+		}
+		g.transformers = append(g.transformers, module...)
+	}
+
+	return g, nil
+}
+
+// Add exported function name mapping from to to
+func (g *generator) Add(name string, from, to *types.Named) error {
+	if !token.IsIdentifier(name) || !token.IsExported(name) {
+		return errors.Errorf("invalid function name %s", name)
+	}
+	if _, found := g.funcNames[name]; found {
+		return errors.Errorf("duplicated function name %s", name)
+	}
+	if _, ok := from.Underlying().(*types.Struct); !ok {
+		return errors.Errorf("%s is not struct type", from)
+	}
+	if _, ok := to.Underlying().(*types.Struct); !ok {
+		return errors.Errorf("%s is not struct type", to)
+	}
+
+	g.funcNames[name] = struct{}{}
+	f := g.mapFuncOf(from, to, lowerFirst(name))
+	f.exported = append(f.exported, name)
+	return nil
+}
+
+// Generate formatted source of package pkgName
+func (g *generator) Generate(pkgName string) ([]byte, error) {
+	// mapFuncOf appends functions of nested structs while generating
+	for i := 0; i < len(g.funcs); i++ {
+		g.generateMapFunc(g.funcs[i])
+	}
+	if len(g.errs) > 0 {
+		return nil, errors.Errorf("can't generate mapping:\n\t%s", strings.Join(g.errs, "\n\t"))
+	}
+
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// Code generated by structmapper-gen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&src, "package %s\n\n", pkgName)
+
+	// standard packages first
+	paths := make([]string, 0, len(g.imports))
+	for path := range g.imports {
+		paths = append(paths, path)
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		if std1, std2 := isStandardPackage(paths[i]), isStandardPackage(paths[j]); std1 != std2 {
+			return std1
+		}
+		return paths[i] < paths[j]
+	})
+	fmt.Fprintf(&src, "import (\n")
+	for i, path := range paths {
+		if i > 0 && isStandardPackage(paths[i-1]) != isStandardPackage(path) {
+			fmt.Fprintf(&src, "\n")
+		}
+		if name := g.imports[path]; name != path[strings.LastIndex(path, "/")+1:] {
+			fmt.Fprintf(&src, "%s ", name)
+		}
+		fmt.Fprintf(&src, "%q\n", path)
+	}
+	fmt.Fprintf(&src, ")\n")
+
+	for _, f := range g.funcs {
+		for _, name := range f.exported {
+			fmt.Fprintf(&src, "\n// %s maps %s to %s\n", name, g.typeString(f.from), g.typeString(f.to))
+			fmt.Fprintf(&src, "func %s(from *%s) (*%s, error) {\n", name, g.typeString(f.from), g.typeString(f.to))
+			fmt.Fprintf(&src, "if from == nil {\nreturn nil, nil\n}\n")
+			fmt.Fprintf(&src, "return %s(from, %q)\n}\n", f.name, f.from.Obj().Name())
+		}
+	}
+	for _, f := range g.funcs {
+		fmt.Fprintf(&src, "\nfunc %s(from *%s, path string) (*%s, error) {\n", f.name, g.typeString(f.from), g.typeString(f.to))
+		src.Write(f.body)
+		fmt.Fprintf(&src, "}\n")
+	}
+
+	formatted, err := format.Source(src.Bytes())
+	if err != nil {
+		return nil, errors.Wrapf(err, "can't format generated source\n%s", src.Bytes())
+	}
+	return formatted, nil
+}
+
+// mapFuncOf returns function mapping from to to, adding it to generate unless exists
+func (g *generator) mapFuncOf(from, to *types.Named, name string) *mapFunc {
+	key := pairKey(from, to)
+	if f, ok := g.funcsByPair[key]; ok {
+		return f
+	}
+
+	if name == "" {
+		name = "map" + upperFirst(packageName(from)) + from.Obj().Name() + "To" + upperFirst(packageName(to)) + to.Obj().Name()
+	}
+	for base, i := name, 2; ; i++ {
+		if _, found := g.funcNames[name]; !found {
+			break
+		}
+		name = base + strconv.Itoa(i)
+	}
+	g.funcNames[name] = struct{}{}
+
+	f := &mapFunc{name: name, from: from, to: to}
+	g.funcs = append(g.funcs, f)
+	g.funcsByPair[key] = f
+	return f
+}
+
+func (g *generator) generateMapFunc(f *mapFunc) {
+	g.buf = new(bytes.Buffer)
+	g.tmps = 0
+
+	g.printf("to := new(%s)", g.typeString(f.to))
+	g.callHook(f.to, beforeMapperType, "BeforeMap")
+	g.mapFields(f.from, f.to)
+	g.callHook(f.to, afterMapperType, "AfterMap")
+	g.printf("return to, nil")
+
+	f.body = g.buf.Bytes()
+}
+
+// callHook generates calling method of hook interface implemented by destination, as runHooks does
+func (g *generator) callHook(to *types.Named, hookType *types.Interface, method string) {
+	if types.Implements(types.NewPointer(to), hookType) {
+		g.printf("if err := to.%s(from); err != nil {", method)
+		g.printf("return nil, %s", g.mappingError(rootPath))
+		g.printf("}")
+	}
+}
+
+// field of struct flattened as deepFields does
+type field struct {
+	*types.Var
+	structmapper.FieldMapping
+	// embedded fields to select this field
+	embedded []*types.Var
+}
+
+func (g *generator) fieldsOf(t types.Type) []field {
+	var fields []field
+
+	s, ok := indirect(t).Underlying().(*types.Struct)
+	if !ok {
+		return nil
+	}
+	for i := 0; i < s.NumFields(); i++ {
+		v := s.Field(i)
+		structField := reflect.StructField{Name: v.Name(), Tag: reflect.StructTag(s.Tag(i))}
+		if !v.Exported() {
+			structField.PkgPath = v.Pkg().Path()
+		}
+		mapping, err := structmapper.FieldMappingOf(structField, g.tagNames...)
+		if err != nil {
+			g.errs = append(g.errs, fmt.Sprintf("%s.%s: %v", types.TypeString(indirect(t), nil), v.Name(), err))
+		}
+
+		if v.Embedded() || mapping.Tag.Inline && isStruct(indirect(v.Type())) {
+			for _, f := range g.fieldsOf(v.Type()) {
+				f.embedded = append([]*types.Var{v}, f.embedded...)
+				fields = append(fields, f)
+			}
+			continue
+		}
+		if mapping.Tag.Inline {
+			g.errs = append(g.errs, fmt.Sprintf("%s.%s: inline field of %s is not struct", types.TypeString(indirect(t), nil), v.Name(), types.TypeString(v.Type(), nil)))
+		}
+
+		fields = append(fields, field{Var: v, FieldMapping: mapping})
+	}
+
+	return fields
+}
+
+// mapFields generates copying fields of from to to, matched as compileStructPlan does
+func (g *generator) mapFields(from, to *types.Named) {
+	fromFields := g.fieldsOf(from)
+	toFields := g.fieldsOf(to)
+
+	toFieldsByName := make(map[string]*field)
+	for i := range toFields {
+		for _, name := range toFields[i].Names {
+			if _, found := toFieldsByName[name]; !found {
+				toFieldsByName[name] = &toFields[i]
+			}
+		}
+	}
+
+	copied := make(map[*field]struct{})
+	for i := range fromFields {
+		fromField := &fromFields[i]
+		if !fromField.Exported() || fromField.Tag.WriteOnly {
+			continue
+		}
+
+		mapped := false
+		for _, name := range fromField.Names {
+			if toField, found := toFieldsByName[name]; found {
+				mapped = true
+				if _, ok := copied[toField]; !ok {
+					if toField.Exported() && !toField.Tag.ReadOnly {
+						if err := g.copyField(fromField, toField); err != nil {
+							g.errs = append(g.errs, fmt.Sprintf("%s.%s: %v", from.Obj().Name(), fromField.Name(), err))
+						}
+					}
+					copied[toField] = struct{}{}
+				}
+			}
+		}
+
+		if !mapped && !fromField.Ignored {
+			g.errs = append(g.errs, fmt.Sprintf("%s.%s: %v: no destination field in %s", from.Obj().Name(), fromField.Name(), structmapper.ErrUnmappedField, to))
+		}
+	}
+
+	for i := range toFields {
+		toField := &toFields[i]
+		if _, ok := copied[toField]; !ok && !toField.Ignored && !toField.Tag.ReadOnly {
+			g.errs = append(g.errs, fmt.Sprintf("%s.%s: %v: destination field %s received no value", from.Obj().Name(), toField.Name(), structmapper.ErrUnmappedField, toField.Name()))
+		}
+	}
+}
+
+// copyField generates copying field as copyStruct and copyValue do
+func (g *generator) copyField(fromField, toField *field) error {
+	for _, v := range append(fromField.embedded, toField.embedded...) {
+		if !v.Exported() {
+			return errors.Errorf("unexported embedded field %s is not supported", v.Name())
+		}
+	}
+
+	// closing braces
+	blocks := 0
+	defer func() {
+		for ; blocks > 0; blocks-- {
+			g.printf("}")
+		}
+	}()
+
+	src := "from"
+	for _, v := range fromField.embedded {
+		src += "." + v.Name()
+		if isPointer(v.Type()) {
+			g.printf("if %s != nil {", src)
+			blocks++
+		}
+	}
+	src += "." + fromField.Name()
+
+	// nil pointer is checked by policy
+	nonNil := false
+	switch policy := max(fromField.Policy, toField.Policy); policy {
+	case structmapper.SkipNil:
+		if isNillable(fromField.Type()) {
+			g.printf("if %s != nil {", src)
+			blocks++
+			nonNil = true
+		}
+	case structmapper.SkipZero:
+		cond, err := g.nonZero(src, fromField.Type())
+		if err != nil {
+			return err
+		}
+		g.printf("if %s {", cond)
+		blocks++
+		nonNil = isNillable(fromField.Type())
+	}
+
+	if isInterface(fromField.Type()) {
+		return errors.Errorf("interface type %s is not supported", g.typeString(fromField.Type()))
+	}
+
+	// nil pointer leaves zero value
+	from := operand{expr: src, typ: fromField.Type(), nonNil: nonNil}
+	for isPointer(from.typ) {
+		if !from.nonNil {
+			g.printf("if %s != nil {", from.value())
+			blocks++
+		}
+		from = operand{expr: from.value(), typ: elem(from.typ), ptr: true}
+	}
+
+	toType := toField.Type()
+	if isPointer(toType) && isPointer(elem(toType)) {
+		return errors.Errorf("pointer to pointer %s is not supported", g.typeString(toType))
+	}
+
+	path := rootPath.field(fromField.Name())
+	if fromField.Tag.String || toField.Tag.String {
+		from = g.convertByString(from, indirect(toType), path)
+	}
+
+	v, err := g.convert(from, indirect(toType), path)
+	if err != nil {
+		return err
+	}
+	value := g.valueAs(v, toType)
+
+	dest := "to"
+	for _, v := range toField.embedded {
+		dest += "." + v.Name()
+		if isPointer(v.Type()) {
+			g.printf("if %s == nil {", dest)
+			g.printf("%s = new(%s)", dest, g.typeString(elem(v.Type())))
+			g.printf("}")
+		}
+	}
+	g.printf("%s.%s = %s", dest, toField.Name(), value)
+
+	return nil
+}
+
+// convertByString generates converting number or bool to string, or string to number or bool of toType, as convertByString does
+func (g *generator) convertByString(from operand, toType types.Type, path pathExpr) operand {
+	fromBasic, ok1 := from.typ.Underlying().(*types.Basic)
+	toBasic, ok2 := toType.Underlying().(*types.Basic)
+	if !ok1 || !ok2 {
+		return from
+	}
+
+	if toBasic.Info()&types.IsString != 0 {
+		var format string
+		switch info := fromBasic.Info(); {
+		case info&types.IsUnsigned != 0:
+			format = "FormatUint(" + g.conversion(from.value(), from.typ, types.Typ[types.Uint64]) + ", 10)"
+		case info&types.IsInteger != 0:
+			format = "FormatInt(" + g.conversion(from.value(), from.typ, types.Typ[types.Int64]) + ", 10)"
+		case info&types.IsFloat != 0:
+			format = fmt.Sprintf("FormatFloat(%s, 'g', -1, %d)", g.conversion(from.value(), from.typ, types.Typ[types.Float64]), bitSize(fromBasic))
+		case info&types.IsBoolean != 0:
+			format = "FormatBool(" + g.conversion(from.value(), from.typ, types.Typ[types.Bool]) + ")"
+		default:
+			return from
+		}
+		return operand{expr: g.importName("strconv", "strconv") + "." + format, typ: types.Typ[types.String]}
+	}
+	if fromBasic.Info()&types.IsString == 0 {
+		return from
+	}
+
+	var parse string
+	var parsed types.Type
+	s := g.conversion(from.value(), from.typ, types.Typ[types.String])
+	switch info := toBasic.Info(); {
+	case info&types.IsUnsigned != 0:
+		parse, parsed = fmt.Sprintf("ParseUint(%s, 10, %d)", s, bitSize(toBasic)), types.Typ[types.Uint64]
+	case info&types.IsInteger != 0:
+		parse, parsed = fmt.Sprintf("ParseInt(%s, 10, %d)", s, bitSize(toBasic)), types.Typ[types.Int64]
+	case info&types.IsFloat != 0:
+		parse, parsed = fmt.Sprintf("ParseFloat(%s, %d)", s, bitSize(toBasic)), types.Typ[types.Float64]
+	case info&types.IsBoolean != 0:
+		parse, parsed = fmt.Sprintf("ParseBool(%s)", s), types.Typ[types.Bool]
+	default:
+		return from
+	}
+
+	v := g.tmp("v")
+	g.printf("%s, err := %s.%s", v, g.importName("strconv", "strconv"), parse)
+	g.returnOnError(path)
+	return operand{expr: g.conversion(v, parsed, toType), typ: toType}
+}
+
+// convert generates converting from to value of toType as convertValue does
+func (g *generator) convert(from operand, toType types.Type, path pathExpr) (operand, error) {
+	if t := g.transformerOf(from.typ, toType); t != nil {
+		return t.emit(g, from, toType, path)
+
+	} else if isInterface(from.typ) || isInterface(toType) {
+		return operand{}, errors.Errorf("interface type %s -> %s is not supported", g.typeString(from.typ), g.typeString(toType))
+
+	} else if isSlice(from.typ) && isArray(toType) {
+		return operand{}, errors.Errorf("slice to array %s -> %s is not supported", g.typeString(from.typ), g.typeString(toType))
+
+	} else if types.ConvertibleTo(from.typ, toType) && !copiedByPlan(toType, map[types.Type]bool{}) {
+		return operand{expr: g.conversion(from.value(), from.typ, toType), typ: toType}, nil
+
+	} else if types.Implements(types.NewPointer(toType), scannerType) {
+		return g.scan(from, toType, path), nil
+
+	} else if isPointer(from.typ) {
+		return g.convertPointer(from, toType, path)
+
+	} else if isStruct(from.typ) && isStruct(toType) {
+		return g.convertStruct(from, toType, path)
+
+	} else if (isSlice(from.typ) || isArray(from.typ)) && isSlice(toType) {
+		return g.convertSlice(from, toType, path)
+
+	} else if isMap(from.typ) && isMap(toType) {
+		return g.convertMap(from, toType, path)
+
+	} else {
+		return operand{}, errors.Errorf("can't convert %s -> %s", g.typeString(from.typ), g.typeString(toType))
+
+	}
+}
+
+func (g *generator) transformerOf(fromType, toType types.Type) *transformer {
+	for i := range g.transformers {
+		if g.transformers[i].matches(fromType, toType) {
+			return &g.transformers[i]
+		}
+	}
+	return nil
+}
+
+func (g *generator) scan(from operand, toType types.Type, path pathExpr) operand {
+	v := g.tmp("v")
+	g.printf("var %s %s", v, g.typeString(toType))
+	g.printf("if err := %s.Scan(%s); err != nil {", v, from.value())
+	g.printf("return nil, %s", g.mappingError(path))
+	g.printf("}")
+	return operand{expr: v, typ: toType}
+}
+
+// convertPointer converts element of non-nil pointer, or leaves zero value
+func (g *generator) convertPointer(from operand, toType types.Type, path pathExpr) (operand, error) {
+	ptr := from.value()
+	if from.nonNil {
+		return g.convert(operand{expr: ptr, typ: elem(from.typ), ptr: true}, toType, path)
+	}
+
+	var converted operand
+	body, err := g.capture(func() (err error) {
+		converted, err = g.convert(operand{expr: ptr, typ: elem(from.typ), ptr: true}, toType, path)
+		return err
+	})
+	if err != nil {
+		return operand{}, err
+	}
+
+	v := g.tmp("v")
+	if converted.ptr {
+		g.printf("%s := new(%s)", v, g.typeString(toType))
+	} else {
+		g.printf("var %s %s", v, g.typeString(toType))
+	}
+	g.printf("if %s != nil {", ptr)
+	g.buf.Write(body)
+	g.printf("%s = %s", v, converted.expr)
+	g.printf("}")
+
+	return operand{expr: v, typ: toType, ptr: converted.ptr}, nil
+}
+
+func (g *generator) convertStruct(from operand, toType types.Type, path pathExpr) (operand, error) {
+	fromNamed, ok1 := types.Unalias(from.typ).(*types.Named)
+	toNamed, ok2 := types.Unalias(toType).(*types.Named)
+	if !ok1 || !ok2 {
+		return operand{}, errors.Errorf("unnamed struct %s -> %s is not supported", g.typeString(from.typ), g.typeString(toType))
+	}
+
+	f := g.mapFuncOf(fromNamed, toNamed, "")
+	v := g.tmp("v")
+	g.printf("%s, err := %s(%s, %s)", v, f.name, from.addr(), g.pathString(path))
+	g.printf("if err != nil {")
+	g.printf("return nil, err")
+	g.printf("}")
+	return operand{expr: v, typ: toType, ptr: true}, nil
+}
+
+func (g *generator) convertSlice(from operand, toType types.Type, path pathExpr) (operand, error) {
+	src := from.index()
+	destType := elem(toType)
+	if isPointer(destType) && isPointer(elem(destType)) {
+		return operand{}, errors.Errorf("pointer to pointer %s is not supported", g.typeString(destType))
+	}
+
+	v, i := g.tmp("v"), g.tmp("i")
+	var converted operand
+	body, err := g.capture(func() (err error) {
+		converted, err = g.convert(operand{expr: src + "[" + i + "]", typ: elem(from.typ)}, indirect(destType), path.index(i))
+		if err == nil {
+			g.printf("%s = append(%s, %s)", v, v, g.valueAs(converted, destType))
+		}
+		return err
+	})
+	if err != nil {
+		return operand{}, err
+	}
+
+	g.printf("var %s %s", v, g.typeString(toType))
+	if isSlice(from.typ) {
+		g.printf("if %s != nil {", src)
+	} else {
+		g.printf("{")
+	}
+	g.printf("%s = make(%s, 0, len(%s))", v, g.typeString(toType), src)
+	g.printf("for %s := range %s {", i, src)
+	g.buf.Write(body)
+	g.printf("}")
+	g.printf("}")
+
+	return operand{expr: v, typ: toType}, nil
+}
+
+func (g *generator) convertMap(from operand, toType types.Type, path pathExpr) (operand, error) {
+	src := from.index()
+	fromMap := types.Unalias(from.typ).Underlying().(*types.Map)
+	toMap := types.Unalias(toType).Underlying().(*types.Map)
+
+	v, k, e := g.tmp("v"), g.tmp("k"), g.tmp("e")
+	body, err := g.capture(func() error {
+		key, err := g.convertElem(operand{expr: k, typ: fromMap.Key()}, toMap.Key(), path.key(k))
+		if err != nil {
+			return err
+		}
+		value, err := g.convertElem(operand{expr: e, typ: fromMap.Elem()}, toMap.Elem(), path.key(k))
+		if err != nil {
+			return err
+		}
+		g.printf("%s[%s] = %s", v, key, value)
+		return nil
+	})
+	if err != nil {
+		return operand{}, err
+	}
+
+	g.printf("var %s %s", v, g.typeString(toType))
+	g.printf("if %s != nil {", src)
+	g.printf("%s = make(%s, len(%s))", v, g.typeString(toType), src)
+	g.printf("for %s, %s := range %s {", k, e, src)
+	g.buf.Write(body)
+	g.printf("}")
+	g.printf("}")
+
+	return operand{expr: v, typ: toType}, nil
+}
+
+// convertElem returns expression of toType converted from element of map as convertElem does
+func (g *generator) convertElem(from operand, toType types.Type, path pathExpr) (string, error) {
+	if isPointer(toType) && isPointer(elem(toType)) {
+		return "", errors.Errorf("pointer to pointer %s is not supported", g.typeString(toType))
+	}
+
+	if !isPointer(from.typ) || !isPointer(toType) {
+		converted, err := g.convert(from, indirect(toType), path)
+		if err != nil {
+			return "", err
+		}
+		return g.valueAs(converted, toType), nil
+	}
+
+	// nil pointer to nil pointer
+	var value string
+	body, err := g.capture(func() error {
+		from.nonNil = true
+		converted, err := g.convert(from, indirect(toType), path)
+		if err == nil {
+			value = g.valueAs(converted, toType)
+		}
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	v := g.tmp("v")
+	g.printf("var %s %s", v, g.typeString(toType))
+	g.printf("if %s != nil {", from.value())
+	g.buf.Write(body)
+	g.printf("%s = %s", v, value)
+	g.printf("}")
+	return v, nil
+}
+
+// valueAs returns expression of v as t, which is type of v or pointer to it
+func (g *generator) valueAs(v operand, t types.Type) string {
+	if !isPointer(t) {
+		return v.value()
+	} else if v.ptr {
+		return v.expr
+	}
+
+	tmp := g.tmp("v")
+	g.printf("%s := %s", tmp, v.expr)
+	return "&" + tmp
+}
+
+// conversion returns expression converting x of fromType to toType
+func (g *generator) conversion(x string, fromType, toType types.Type) string {
+	if types.Identical(fromType, toType) {
+		return x
+	}
+
+	t := g.typeString(toType)
+	switch types.Unalias(toType).(type) {
+	case *types.Pointer, *types.Signature, *types.Chan:
+		t = "(" + t + ")"
+	}
+	return t + "(" + x + ")"
+}
+
+// nonZero returns condition that x of type t is not zero as reflect.Value.IsZero
+func (g *generator) nonZero(x string, t types.Type) (string, error) {
+	if isNillable(t) {
+		return x + " != nil", nil
+	}
+
+	if b, ok := t.Underlying().(*types.Basic); ok {
+		switch info := b.Info(); {
+		case info&types.IsBoolean != 0:
+			return x, nil
+		case info&types.IsString != 0:
+			return x + ` != ""`, nil
+		case info&types.IsNumeric != 0:
+			return x + " != 0", nil
+		}
+	}
+
+	if types.Comparable(t) {
+		return x + " != (" + g.typeString(t) + "{})", nil
+	}
+	return "", errors.Errorf("omitempty of %s is not supported", g.typeString(t))
+}
+
+func (g *generator) mappingError(path pathExpr) string {
+	return fmt.Sprintf("&%s.MappingError{Path: %s, Err: err}", g.importName(structmapperPath, "structmapper"), g.pathString(path))
+}
+
+// returnOnError generates returning err with path
+func (g *generator) returnOnError(path pathExpr) {
+	g.printf("if err != nil {")
+	g.printf("return nil, %s", g.mappingError(path))
+	g.printf("}")
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(g.buf, format, args...)
+	g.buf.WriteByte('\n')
+}
+
+// capture returns source generated by fn
+func (g *generator) capture(fn func() error) ([]byte, error) {
+	buf := g.buf
+	g.buf = new(bytes.Buffer)
+	defer func() {
+		g.buf = buf
+	}()
+
+	err := fn()
+	return g.buf.Bytes(), err
+}
+
+// tmp returns new variable name
+func (g *generator) tmp(prefix string) string {
+	g.tmps++
+	return prefix + strconv.Itoa(g.tmps)
+}
+
+func (g *generator) typeString(t types.Type) string {
+	return types.TypeString(t, func(pkg *types.Package) string {
+		if pkg.Path() == g.localPath {
+			return ""
+		}
+		return g.importName(pkg.Path(), pkg.Name())
+	})
+}
+
+// importName adds import of path, and returns its name
+func (g *generator) importName(path, name string) string {
+	if alias, ok := aliasPackages[path]; ok {
+		path, name = alias, alias[strings.LastIndex(alias, "/")+1:]
+	}
+	if name, ok := g.imports[path]; ok {
+		return name
+	}
+
+	for base, i := name, 2; ; i++ {
+		if _, found := g.importNames[name]; !found {
+			break
+		}
+		name = base + strconv.Itoa(i)
+	}
+	g.imports[path] = name
+	g.importNames[name] = path
+	return name
+}
+
+// operand is expression of source value
+type operand struct {
+	expr string
+	typ  types.Type
+	// expr is non-nil pointer to typ
+	ptr bool
+	// pointer typ is checked non-nil
+	nonNil bool
+}
+
+func (o operand) value() string {
+	if o.ptr {
+		return "*" + o.expr
+	}
+	return o.expr
+}
+
+// addr returns pointer to value, which is addressable unless ptr
+func (o operand) addr() string {
+	if o.ptr {
+		return o.expr
+	}
+	return "&" + o.expr
+}
+
+// selector returns expression to select field or method of value
+func (o operand) selector() string {
+	if o.ptr && isPointer(o.typ) {
+		return "(*" + o.expr + ")"
+	}
+	return o.expr
+}
+
+// index returns expression to index or range over value
+func (o operand) index() string {
+	if o.ptr {
+		return "(*" + o.expr + ")"
+	}
+	return o.expr
+}
+
+// pathExpr is expression of source path concatenating string literals and calls, as copier.pathString
+type pathExpr []pathPart
+
+type pathPart struct {
+	literal bool
+	s       string
+	// package of called function
+	pkg string
+}
+
+var rootPath = pathExpr{{s: "path"}}
+
+func (p pathExpr) field(name string) pathExpr {
+	return p.append(pathPart{literal: true, s: "." + name})
+}
+
+// index appends indexSegment of i
+func (p pathExpr) index(i string) pathExpr {
+	return p.append(pathPart{literal: true, s: "["}, pathPart{pkg: "strconv", s: "Itoa(" + i + ")"}, pathPart{literal: true, s: "]"})
+}
+
+// key appends keySegment of k
+func (p pathExpr) key(k string) pathExpr {
+	return p.append(pathPart{literal: true, s: "["}, pathPart{pkg: "fmt", s: "Sprint(" + k + ")"}, pathPart{literal: true, s: "]"})
+}
+
+func (p pathExpr) append(parts ...pathPart) pathExpr {
+	appended := append(pathExpr{}, p...)
+	for _, part := range parts {
+		if last := len(appended) - 1; part.literal && appended[last].literal {
+			appended[last].s += part.s
+		} else {
+			appended = append(appended, part)
+		}
+	}
+	return appended
+}
+
+// pathString returns expression of path, adding imports of called functions
+func (g *generator) pathString(path pathExpr) string {
+	exprs := make([]string, 0, len(path))
+	for _, part := range path {
+		switch {
+		case part.literal:
+			exprs = append(exprs, strconv.Quote(part.s))
+		case part.pkg != "":
+			exprs = append(exprs, g.importName(part.pkg, part.pkg)+"."+part.s)
+		default:
+			exprs = append(exprs, part.s)
+		}
+	}
+	return strings.Join(exprs, " + ")
+}
+
+var (
+	errorType = types.Universe.Lookup("error").Type()
+	emptyType = types.NewInterfaceType(nil, nil).Complete()
+
+	// database/sql.Scanner
+	scannerType = newInterface("Scan", types.NewTuple(types.NewParam(token.NoPos, nil, "src", emptyType)), types.NewTuple(types.NewParam(token.NoPos, nil, "", errorType)))
+	// structmapper.BeforeMapper
+	beforeMapperType = newInterface("BeforeMap", types.NewTuple(types.NewParam(token.NoPos, nil, "from", emptyType)), types.NewTuple(types.NewParam(token.NoPos, nil, "", errorType)))
+	// structmapper.AfterMapper
+	afterMapperType = newInterface("AfterMap", types.NewTuple(types.NewParam(token.NoPos, nil, "from", emptyType)), types.NewTuple(types.NewParam(token.NoPos, nil, "", errorType)))
+	// fmt.Stringer
+	stringerType = newInterface("String", nil, types.NewTuple(types.NewParam(token.NoPos, nil, "", types.Typ[types.String])))
+)
+
+func newInterface(method string, params, results *types.Tuple) *types.Interface {
+	sig := types.NewSignatureType(nil, nil, nil, params, results, false)
+	return types.NewInterfaceType([]*types.Func{types.NewFunc(token.NoPos, nil, method, sig)}, nil).Complete()
+}
+
+func pairKey(from, to types.Type) string {
+	return types.TypeString(from, nil) + " -> " + types.TypeString(to, nil)
+}
+
+func packageName(t *types.Named) string {
+	if pkg := t.Obj().Pkg(); pkg != nil {
+		return pkg.Name()
+	}
+	return ""
+}
+
+// bitSize returns bit size of number type t for strconv, 0 for int and uint
+func bitSize(t *types.Basic) int {
+	switch t.Kind() {
+	case types.Int8, types.Uint8:
+		return 8
+	case types.Int16, types.Uint16:
+		return 16
+	case types.Int32, types.Uint32, types.Float32:
+		return 32
+	case types.Int64, types.Uint64, types.Uintptr, types.Float64:
+		return 64
+	default:
+		return 0
+	}
+}
+
+func isPointer(t types.Type) bool {
+	_, ok := t.Underlying().(*types.Pointer)
+	return ok
+}
+
+func isInterface(t types.Type) bool {
+	return types.IsInterface(t)
+}
+
+func isStruct(t types.Type) bool {
+	_, ok := t.Underlying().(*types.Struct)
+	return ok
+}
+
+func isNamedStruct(t types.Type) bool {
+	_, ok := types.Unalias(t).(*types.Named)
+	return ok && isStruct(t)
+}
+
+// hasExportedFields reports whether struct t has exported field, or embedded struct having one
+func hasExportedFields(t types.Type) bool {
+	s := t.Underlying().(*types.Struct)
+	for i := 0; i < s.NumFields(); i++ {
+		if field := s.Field(i); field.Exported() {
+			return true
+		} else if field.Embedded() && isStruct(indirect(field.Type())) && hasExportedFields(indirect(field.Type())) {
+			return true
+		}
+	}
+	return false
+}
+
+// copiedByPlan reports whether t holds named structs copied by mapping function, which are not converted as runtime does
+func copiedByPlan(t types.Type, visited map[types.Type]bool) bool {
+	if visited[t] {
+		return false
+	}
+	visited[t] = true
+
+	switch u := t.Underlying().(type) {
+	case *types.Struct:
+		return isNamedStruct(t) && hasExportedFields(t) && !types.Implements(types.NewPointer(t), scannerType)
+	case *types.Slice:
+		return copiedByPlan(indirect(u.Elem()), visited)
+	case *types.Map:
+		return copiedByPlan(indirect(u.Key()), visited) || copiedByPlan(indirect(u.Elem()), visited)
+	default:
+		return false
+	}
+}
+
+func isSlice(t types.Type) bool {
+	_, ok := t.Underlying().(*types.Slice)
+	return ok
+}
+
+func isArray(t types.Type) bool {
+	_, ok := t.Underlying().(*types.Array)
+	return ok
+}
+
+func isMap(t types.Type) bool {
+	_, ok := t.Underlying().(*types.Map)
+	return ok
+}
+
+func isNillable(t types.Type) bool {
+	switch t.Underlying().(type) {
+	case *types.Pointer, *types.Slice, *types.Map, *types.Interface, *types.Signature, *types.Chan:
+		return true
+	default:
+		return false
+	}
+}
+
+// elem returns element type of pointer, slice, array or map
+func elem(t types.Type) types.Type {
+	return t.Underlying().(interface{ Elem() types.Type }).Elem()
+}
+
+func indirect(t types.Type) types.Type {
+	for isPointer(t) {
+		t = elem(t)
+	}
+	return t
+}
+
+func isStandardPackage(path string) bool {
+	return !strings.Contains(strings.SplitN(path, "/", 2)[0], ".")
+}
+
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	r := []rune(s)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	r := []rune(s)
+	r[0] = unicode.ToLower(r[0])
+	return string(r)
+}
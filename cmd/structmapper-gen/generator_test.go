@@ -0,0 +1,248 @@
+package main
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const fixturePath = "example.com/fixture"
+
+const fixtureSource = `package fixture
+
+import "time"
+
+type Address struct {
+	Zip  string
+	Tags map[string]*Tag
+}
+
+type Tag struct {
+	Name string
+}
+
+type Base struct {
+	ID string ` + "`json:\"id\"`" + `
+}
+
+type User struct {
+	*Base
+	Name      string     ` + "`json:\"name\" structmapper:\",omitempty\"`" + `
+	Addresses []*Address ` + "`json:\"addresses\"`" + `
+	Home      Address    ` + "`json:\"home\"`" + `
+	CreatedAt time.Time  ` + "`json:\"created_at\"`" + `
+	Secret    string     ` + "`json:\"-\"`" + `
+	internal  int
+}
+
+type UserDTO struct {
+	ID        string        ` + "`json:\"id\"`" + `
+	Name      *string       ` + "`json:\"name\"`" + `
+	Addresses []AddressDTO  ` + "`json:\"addresses\"`" + `
+	Home      *AddressDTO   ` + "`json:\"home\"`" + `
+	CreatedAt time.Time     ` + "`json:\"created_at\"`" + `
+}
+
+type AddressDTO struct {
+	Zip  string
+	Tags map[string]*TagDTO
+}
+
+type TagDTO struct {
+	Name string
+}
+
+type BadTag struct {
+	Name []string
+}
+
+type Record struct {
+	Count   int  ` + "`structmapper:\",string\"`" + `
+	Meta    Meta ` + "`structmapper:\",inline\"`" + `
+	Version int  ` + "`structmapper:\",writeonly\"`" + `
+}
+
+type Meta struct {
+	Owner string
+}
+
+type RecordDTO struct {
+	Count   string
+	Owner   string
+	Version int ` + "`structmapper:\",readonly\"`" + `
+}
+
+type Total struct {
+	Price int
+	Tax   int
+}
+
+type TotalDTO struct {
+	Price int
+	Tax   int
+	Sum   int ` + "`structmapper:\",ignore\"`" + `
+}
+
+func (t *TotalDTO) AfterMap(from interface{}) error {
+	t.Sum = t.Price + t.Tax
+	return nil
+}
+
+type BadOption struct {
+	Name string ` + "`structmapper:\",unknown\"`" + `
+}
+
+type Unmatched struct {
+	ID      string ` + "`json:\"id\"`" + `
+	Missing string
+}
+`
+
+// checkFixture type-checks fixture package with extra sources
+func checkFixture(t *testing.T, sources ...string) *types.Package {
+	fset := token.NewFileSet()
+	files := make([]*ast.File, 0, len(sources)+1)
+	for i, src := range append([]string{fixtureSource}, sources...) {
+		file, err := parser.ParseFile(fset, filepath.Join("fixture", string(rune('a'+i))+".go"), src, 0)
+		require.NoError(t, err)
+		files = append(files, file)
+	}
+
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	pkg, err := conf.Check(fixturePath, fset, files, nil)
+	require.NoError(t, err, "%s", sources)
+	return pkg
+}
+
+func lookup(pkg *types.Package, name string) *types.Named {
+	return pkg.Scope().Lookup(name).Type().(*types.Named)
+}
+
+func TestGenerate(t *testing.T) {
+	pkg := checkFixture(t)
+
+	t.Run("nested structs, embedded pointer, map and omitempty", func(t *testing.T) {
+		g, err := newGenerator(fixturePath, []string{"protobuf", "stringer"})
+		require.NoError(t, err)
+		require.NoError(t, g.Add("MapUserToDTO", lookup(pkg, "User"), lookup(pkg, "UserDTO")))
+		require.NoError(t, g.Add("MapDTOToUser", lookup(pkg, "UserDTO"), lookup(pkg, "User")))
+
+		src, err := g.Generate("fixture")
+		require.NoError(t, err)
+
+		// generated into fixture package
+		checkFixture(t, string(src))
+		assert.Contains(t, string(src), "func mapFixtureAddressToFixtureAddressDTO(from *Address, path string) (*AddressDTO, error)")
+		assert.Contains(t, string(src), "to.Base = new(Base)")
+		// by mapping function even if convertible
+		assert.Contains(t, string(src), "func mapFixtureTagToFixtureTagDTO(from *Tag, path string) (*TagDTO, error)")
+	})
+
+	t.Run("unmatched fields", func(t *testing.T) {
+		g, err := newGenerator(fixturePath, nil)
+		require.NoError(t, err)
+		require.NoError(t, g.Add("MapUserToUnmatched", lookup(pkg, "User"), lookup(pkg, "Unmatched")))
+
+		_, err = g.Generate("fixture")
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "User.Name: unmapped field: no destination field in example.com/fixture.Unmatched")
+			assert.Contains(t, err.Error(), "User.Missing: unmapped field: destination field Missing received no value")
+			assert.NotContains(t, err.Error(), "User.Secret")
+		}
+	})
+
+	t.Run("unconvertible field", func(t *testing.T) {
+		g, err := newGenerator(fixturePath, nil)
+		require.NoError(t, err)
+		require.NoError(t, g.Add("MapTagToBadTag", lookup(pkg, "Tag"), lookup(pkg, "BadTag")))
+
+		_, err = g.Generate("fixture")
+		assert.EqualError(t, err, "can't generate mapping:\n\tTag.Name: can't convert string -> []string")
+	})
+
+	t.Run("tag options", func(t *testing.T) {
+		g, err := newGenerator(fixturePath, nil)
+		require.NoError(t, err)
+		require.NoError(t, g.Add("MapRecordToDTO", lookup(pkg, "Record"), lookup(pkg, "RecordDTO")))
+		require.NoError(t, g.Add("MapDTOToRecord", lookup(pkg, "RecordDTO"), lookup(pkg, "Record")))
+
+		src, err := g.Generate("fixture")
+		require.NoError(t, err)
+
+		checkFixture(t, string(src))
+		assert.Contains(t, string(src), "strconv.FormatInt(int64(from.Count), 10)")
+		assert.Contains(t, string(src), "strconv.ParseInt(from.Count, 10, 0)")
+		assert.Contains(t, string(src), "to.Meta.Owner = from.Owner")
+		assert.Contains(t, string(src), "to.Version = from.Version")
+		assert.Equal(t, 1, strings.Count(string(src), "to.Version ="))
+	})
+
+	t.Run("hook of destination", func(t *testing.T) {
+		g, err := newGenerator(fixturePath, nil)
+		require.NoError(t, err)
+		require.NoError(t, g.Add("MapTotalToDTO", lookup(pkg, "Total"), lookup(pkg, "TotalDTO")))
+
+		src, err := g.Generate("fixture")
+		require.NoError(t, err)
+
+		checkFixture(t, string(src))
+		assert.Contains(t, string(src), "if err := to.AfterMap(from); err != nil {")
+		assert.NotContains(t, string(src), "BeforeMap")
+	})
+
+	t.Run("invalid tag", func(t *testing.T) {
+		g, err := newGenerator(fixturePath, nil)
+		require.NoError(t, err)
+		require.NoError(t, g.Add("MapTagToBadOption", lookup(pkg, "Tag"), lookup(pkg, "BadOption")))
+
+		_, err = g.Generate("fixture")
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), `example.com/fixture.BadOption.Name: unknown option "unknown" in tag ",unknown"`)
+		}
+	})
+
+	t.Run("unknown module", func(t *testing.T) {
+		_, err := newGenerator(fixturePath, []string{"unknown"})
+		assert.EqualError(t, err, "unknown module unknown")
+	})
+}
+
+func TestGeneratedUpToDate(t *testing.T) {
+	dir := filepath.Join("..", "..", "test", "gen")
+	expected, err := os.ReadFile(filepath.Join(dir, "zz_generated.go"))
+	require.NoError(t, err)
+
+	actual, err := run(dir, "gen", []string{"protobuf", "stringer"}, nil, []mapping{
+		{
+			Name: "MapUserToProto",
+			From: "github.com/structmapper/structmapper/test/dto.User",
+			To:   "github.com/structmapper/structmapper/test/proto.User",
+		},
+		{
+			Name: "MapProtoToUser",
+			From: "github.com/structmapper/structmapper/test/proto.User",
+			To:   "github.com/structmapper/structmapper/test/dto.User",
+		},
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, string(expected), string(actual), "run go generate ./test/gen")
+	}
+}
+
+func TestMappingFlags(t *testing.T) {
+	var flags mappingFlags
+	assert.NoError(t, flags.Set("MapAToB=example.com/a.A,example.com/b.B"))
+	assert.Equal(t, mappingFlags{{Name: "MapAToB", From: "example.com/a.A", To: "example.com/b.B"}}, flags)
+
+	assert.Error(t, flags.Set("MapAToB"))
+	assert.Error(t, flags.Set("MapAToB=example.com/a.A"))
+}
@@ -0,0 +1,156 @@
+// Command structmapper-gen generates reflection-free mapping functions between struct types.
+//
+// Generated functions map fields as structmapper.Mapper does with the given modules installed,
+// and generation fails on fields which can't be matched or converted.
+// Generated functions don't detect cycles of pointers, as PreserveReferences is not supported.
+//
+//	//go:generate go run github.com/structmapper/structmapper/cmd/structmapper-gen -o zz_generated.go -map MapUserToProto=github.com/example/dto.User,github.com/example/proto.User
+package main
+
+import (
+	"bytes"
+	"flag"
+	"go/importer"
+	"go/token"
+	"go/types"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// mapping is function to generate, by `-map Name=path/to/pkg.From,path/to/pkg.To`
+type mapping struct {
+	Name string
+	From string
+	To   string
+}
+
+type mappingFlags []mapping
+
+func (f *mappingFlags) String() string {
+	names := make([]string, 0, len(*f))
+	for _, m := range *f {
+		names = append(names, m.Name)
+	}
+	return strings.Join(names, ",")
+}
+
+func (f *mappingFlags) Set(value string) error {
+	name, pair, ok := strings.Cut(value, "=")
+	if !ok {
+		return errors.Errorf("invalid mapping %q, expected Name=From,To", value)
+	}
+	from, to, ok := strings.Cut(pair, ",")
+	if !ok || name == "" || from == "" || to == "" {
+		return errors.Errorf("invalid mapping %q, expected Name=From,To", value)
+	}
+
+	*f = append(*f, mapping{Name: name, From: from, To: to})
+	return nil
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("structmapper-gen: ")
+
+	var mappings mappingFlags
+	flag.Var(&mappings, "map", "function to generate as Name=path/to/pkg.From,path/to/pkg.To (repeatable)")
+	output := flag.String("o", "", "output file (default stdout)")
+	pkgName := flag.String("pkg", os.Getenv("GOPACKAGE"), "package name of output")
+	moduleNames := flag.String("modules", "protobuf,stringer", "built-in modules installed in order")
+	tagNames := flag.String("tags", "structmapper,json", "struct tag keys to match names by in order")
+	flag.Parse()
+
+	if len(mappings) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	dir := "."
+	if *output != "" {
+		dir = filepath.Dir(*output)
+	}
+
+	src, err := run(dir, *pkgName, strings.Split(*moduleNames, ","), strings.Split(*tagNames, ","), mappings)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *output == "" {
+		_, err = os.Stdout.Write(src)
+	} else {
+		err = os.WriteFile(*output, src, 0644)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// run generates source of mappings into package in dir
+func run(dir, pkgName string, moduleNames, tagNames []string, mappings []mapping) ([]byte, error) {
+	if pkgName == "" {
+		return nil, errors.New("package name is required by -pkg or $GOPACKAGE")
+	}
+
+	g, err := newGenerator(localImportPath(dir), moduleNames)
+	if err != nil {
+		return nil, err
+	}
+	g.tagNames = tagNames
+
+	imp := importer.ForCompiler(token.NewFileSet(), "source", nil).(types.ImporterFrom)
+	for _, m := range mappings {
+		from, err := lookupNamed(imp, dir, m.From)
+		if err != nil {
+			return nil, err
+		}
+		to, err := lookupNamed(imp, dir, m.To)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := g.Add(m.Name, from, to); err != nil {
+			return nil, err
+		}
+	}
+
+	return g.Generate(pkgName)
+}
+
+// lookupNamed returns named type by path/to/pkg.Name
+func lookupNamed(imp types.ImporterFrom, dir, ref string) (*types.Named, error) {
+	i := strings.LastIndex(ref, ".")
+	if i < 0 {
+		return nil, errors.Errorf("invalid type %q, expected path/to/pkg.Name", ref)
+	}
+
+	pkg, err := imp.ImportFrom(ref[:i], dir, 0)
+	if err != nil {
+		return nil, errors.Wrapf(err, "can't load %s", ref[:i])
+	}
+
+	obj, ok := pkg.Scope().Lookup(ref[i+1:]).(*types.TypeName)
+	if !ok {
+		return nil, errors.Errorf("type %s is not found", ref)
+	}
+	named, ok := types.Unalias(obj.Type()).(*types.Named)
+	if !ok {
+		return nil, errors.Errorf("type %s is not named type", ref)
+	}
+	return named, nil
+}
+
+// localImportPath returns import path of package in dir, or empty if it's not resolved
+func localImportPath(dir string) string {
+	cmd := exec.Command("go", "list", "-f", "{{.ImportPath}}", ".")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return string(bytes.TrimSpace(out))
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"go/types"
+
+	"github.com/pkg/errors"
+)
+
+// transformer generates conversion equivalent to Transformer registered by built-in module
+type transformer struct {
+	matches func(fromType, toType types.Type) bool
+	emit    func(g *generator, from operand, toType types.Type, path pathExpr) (operand, error)
+}
+
+// Built-in modules by name, of transformers in order of registration
+var modules = map[string][]transformer{
+	"protobuf": protobufModule(),
+	"stringer": stringerModule(),
+}
+
+const (
+	ptypesPath    = "github.com/golang/protobuf/ptypes"
+	timestampPath = "google.golang.org/protobuf/types/known/timestamppb"
+	wrappersPath  = "google.golang.org/protobuf/types/known/wrapperspb"
+	timePath      = "time"
+	timeTypeName  = "Time"
+	timestampName = "Timestamp"
+)
+
+// same as ProtobufModule
+func protobufModule() []transformer {
+	transformers := []transformer{
+		// string -> Timestamp
+		{
+			matches: func(fromType, toType types.Type) bool {
+				return types.Identical(fromType, types.Typ[types.String]) && isNamed(toType, timestampPath, timestampName)
+			},
+			emit: func(g *generator, from operand, toType types.Type, path pathExpr) (operand, error) {
+				t, ts := g.tmp("t"), g.tmp("ts")
+				g.printf("%s, err := %s.Parse(%s.RFC3339, %s)", t, g.importName(timePath, "time"), g.importName(timePath, "time"), from.value())
+				g.returnOnError(path)
+				g.printf("%s, err := %s.TimestampProto(%s)", ts, g.importName(ptypesPath, "ptypes"), t)
+				g.returnOnError(path)
+				return operand{expr: ts, typ: toType, ptr: true}, nil
+			},
+		},
+		// Timestamp -> string
+		{
+			matches: func(fromType, toType types.Type) bool {
+				return isNamed(fromType, timestampPath, timestampName) && types.Identical(toType, types.Typ[types.String])
+			},
+			emit: func(g *generator, from operand, toType types.Type, path pathExpr) (operand, error) {
+				t := g.tmp("t")
+				g.printf("%s, err := %s.Timestamp(%s)", t, g.importName(ptypesPath, "ptypes"), from.addr())
+				g.returnOnError(path)
+				return operand{expr: t + ".Format(" + g.importName(timePath, "time") + ".RFC3339)", typ: toType}, nil
+			},
+		},
+		// time.Time -> Timestamp
+		{
+			matches: func(fromType, toType types.Type) bool {
+				return isNamed(fromType, timePath, timeTypeName) && isNamed(toType, timestampPath, timestampName)
+			},
+			emit: func(g *generator, from operand, toType types.Type, path pathExpr) (operand, error) {
+				ts := g.tmp("ts")
+				g.printf("%s, err := %s.TimestampProto(%s)", ts, g.importName(ptypesPath, "ptypes"), from.value())
+				g.returnOnError(path)
+				return operand{expr: ts, typ: toType, ptr: true}, nil
+			},
+		},
+		// Timestamp -> time.Time
+		{
+			matches: func(fromType, toType types.Type) bool {
+				return isNamed(fromType, timestampPath, timestampName) && isNamed(toType, timePath, timeTypeName)
+			},
+			emit: func(g *generator, from operand, toType types.Type, path pathExpr) (operand, error) {
+				t := g.tmp("t")
+				g.printf("%s, err := %s.Timestamp(%s)", t, g.importName(ptypesPath, "ptypes"), from.addr())
+				g.returnOnError(path)
+				return operand{expr: t, typ: toType}, nil
+			},
+		},
+	}
+
+	for _, w := range protoWrappers {
+		transformers = append(transformers, w.asProto(), w.asValue())
+	}
+	return transformers
+}
+
+// protoWrapper is same as protoTypeMapping
+type protoWrapper struct {
+	name            string
+	acceptableTypes []types.Type
+	// kind of source value read by reflect.Value.Int, Float, Bool or String
+	kind types.BasicInfo
+	// type of Value field
+	valueType types.Type
+}
+
+var protoWrappers = []protoWrapper{
+	{
+		name:            "Int64Value",
+		acceptableTypes: []types.Type{types.Typ[types.Int], types.Typ[types.Int32], types.Typ[types.Int64]},
+		kind:            types.IsInteger,
+		valueType:       types.Typ[types.Int64],
+	},
+	{
+		name:            "Int32Value",
+		acceptableTypes: []types.Type{types.Typ[types.Int], types.Typ[types.Int32], types.Typ[types.Int64]},
+		kind:            types.IsInteger,
+		valueType:       types.Typ[types.Int32],
+	},
+	{
+		name:            "DoubleValue",
+		acceptableTypes: []types.Type{types.Typ[types.Float32], types.Typ[types.Float64]},
+		kind:            types.IsFloat,
+		valueType:       types.Typ[types.Float64],
+	},
+	{
+		name:            "FloatValue",
+		acceptableTypes: []types.Type{types.Typ[types.Float32], types.Typ[types.Float64]},
+		kind:            types.IsFloat,
+		valueType:       types.Typ[types.Float32],
+	},
+	{
+		name:            "BoolValue",
+		acceptableTypes: []types.Type{types.Typ[types.Bool]},
+		kind:            types.IsBoolean,
+		valueType:       types.Typ[types.Bool],
+	},
+	{
+		name:            "StringValue",
+		acceptableTypes: []types.Type{types.Typ[types.String]},
+		kind:            types.IsString,
+		valueType:       types.Typ[types.String],
+	},
+}
+
+func (w protoWrapper) containsInAcceptableTypes(t types.Type) bool {
+	for _, at := range w.acceptableTypes {
+		if types.ConvertibleTo(t, at) {
+			return true
+		}
+	}
+	return false
+}
+
+// value -> wrapper
+func (w protoWrapper) asProto() transformer {
+	return transformer{
+		matches: func(fromType, toType types.Type) bool {
+			return isNamed(toType, wrappersPath, w.name) && w.containsInAcceptableTypes(fromType)
+		},
+		emit: func(g *generator, from operand, toType types.Type, _ pathExpr) (operand, error) {
+			// unsigned integer or unmatched kind panics in reflect.Value.Int or the like
+			basic, ok := from.typ.Underlying().(*types.Basic)
+			if !ok || basic.Info()&w.kind == 0 || basic.Info()&types.IsUnsigned != 0 {
+				return operand{}, errors.Errorf("can't convert %s -> %s", g.typeString(from.typ), g.typeString(toType))
+			}
+
+			value := g.conversion(from.value(), from.typ, w.valueType)
+			return operand{expr: "&" + g.typeString(toType) + "{Value: " + value + "}", typ: toType, ptr: true}, nil
+		},
+	}
+}
+
+// wrapper -> value
+func (w protoWrapper) asValue() transformer {
+	return transformer{
+		matches: func(fromType, toType types.Type) bool {
+			return isNamed(fromType, wrappersPath, w.name) && w.containsInAcceptableTypes(toType)
+		},
+		emit: func(g *generator, from operand, toType types.Type, _ pathExpr) (operand, error) {
+			if !types.ConvertibleTo(w.valueType, toType) {
+				return operand{}, errors.Errorf("can't convert %s -> %s", g.typeString(from.typ), g.typeString(toType))
+			}
+			return operand{expr: g.conversion(from.selector()+".Value", w.valueType, toType), typ: toType}, nil
+		},
+	}
+}
+
+// same as StringerModule
+func stringerModule() []transformer {
+	return []transformer{
+		// *.String() -> string
+		{
+			matches: func(fromType, toType types.Type) bool {
+				return types.Implements(fromType, stringerType) && types.AssignableTo(toType, types.Typ[types.String])
+			},
+			emit: func(g *generator, from operand, toType types.Type, _ pathExpr) (operand, error) {
+				if !isNillable(from.typ) {
+					return operand{expr: from.selector() + ".String()", typ: toType}, nil
+				}
+
+				// nil is empty string
+				v := g.tmp("v")
+				g.printf("var %s string", v)
+				g.printf("if %s != nil {", from.value())
+				g.printf("%s = %s.String()", v, from.selector())
+				g.printf("}")
+				return operand{expr: v, typ: toType}, nil
+			},
+		},
+	}
+}
+
+func isNamed(t types.Type, pkgPath, name string) bool {
+	named, ok := types.Unalias(t).(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == pkgPath && named.Obj().Name() == name
+}
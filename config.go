@@ -0,0 +1,40 @@
+package structmapper
+
+import (
+	"sync"
+)
+
+// Configuration of mapper, replaced as a whole on every registration or configuration.
+// A copy reads one configuration throughout, and struct infos and plans are cached per configuration,
+// so that plans compiled before a registration are never used after it.
+type mapperConfig struct {
+	tagNames     []string
+	nameMatchers []NameMatcher
+	options      copyOptions
+	logger       Logger
+	structInfos  sync.Map // reflect.Type -> *structInfo
+	structPlans  sync.Map // Target -> *structPlan
+}
+
+// clone returns configuration of same settings without caches
+func (c *mapperConfig) clone() *mapperConfig {
+	return &mapperConfig{
+		tagNames:     c.tagNames,
+		nameMatchers: c.nameMatchers,
+		options:      c.options,
+		logger:       c.logger,
+	}
+}
+
+// configure replaces configuration by clone of current one updated by fn, or only drops caches if fn is nil.
+// It must be called after registration is stored.
+func (m *mapper) configure(fn func(config *mapperConfig)) {
+	m.configLock.Lock()
+	defer m.configLock.Unlock()
+
+	config := m.config.Load().clone()
+	if fn != nil {
+		fn(config)
+	}
+	m.config.Store(config)
+}
@@ -0,0 +1,105 @@
+package structmapper
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type localeKey struct{}
+
+type Label string
+
+func upperTransformer(ctx context.Context, from reflect.Value, _ reflect.Type) (reflect.Value, error) {
+	if ctx.Value(localeKey{}) == "upper" {
+		return reflect.ValueOf(Label(strings.ToUpper(from.String()))), nil
+	}
+	return reflect.ValueOf(Label(from.String())), nil
+}
+
+func TestCopyToContext(t *testing.T) {
+	stringToLabel := Target{From: reflect.TypeOf(""), To: reflect.TypeOf(Label(""))}
+
+	t.Run("context transformer", func(t *testing.T) {
+		mapper := New().RegisterContextTransformer(stringToLabel, upperTransformer)
+		ctx := context.WithValue(context.Background(), localeKey{}, "upper")
+
+		to := new(struct{ Zip Label })
+		if assert.NoError(t, mapper.From(&AddressDTO{Zip: "abc"}).CopyToContext(ctx, to)) {
+			assert.Equal(t, Label("ABC"), to.Zip)
+		}
+
+		// without context
+		if assert.NoError(t, mapper.From(&AddressDTO{Zip: "abc"}).CopyTo(to)) {
+			assert.Equal(t, Label("abc"), to.Zip)
+		}
+	})
+
+	t.Run("cancel between slice elements", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		converted := 0
+		mapper := New().RegisterContextTransformer(stringToLabel, func(ctx context.Context, from reflect.Value, _ reflect.Type) (reflect.Value, error) {
+			converted++
+			cancel()
+			return reflect.ValueOf(Label(from.String())), nil
+		})
+
+		var to []Label
+		err := mapper.From([]string{"a", "b", "c"}).CollectErrors().CopyToContext(ctx, &to)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 1, converted)
+	})
+
+	t.Run("cancel between fields", func(t *testing.T) {
+		type Labels struct{ A, B, C Label }
+		from := struct{ A, B, C string }{"a", "b", "c"}
+
+		for name, c := range map[string]struct{ from, to interface{} }{
+			"struct to struct": {from, new(Labels)},
+			"struct to map":    {from, new(map[string]Label)},
+			"map to struct":    {map[string]string{"A": "a", "B": "b", "C": "c"}, new(Labels)},
+		} {
+			t.Run(name, func(t *testing.T) {
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+
+				converted := 0
+				mapper := New().RegisterContextTransformer(stringToLabel, func(ctx context.Context, from reflect.Value, _ reflect.Type) (reflect.Value, error) {
+					converted++
+					cancel()
+					return reflect.ValueOf(Label(from.String())), nil
+				})
+
+				err := mapper.From(c.from).CopyToContext(ctx, c.to)
+				assert.ErrorIs(t, err, context.Canceled)
+				assert.Equal(t, 1, converted)
+			})
+		}
+	})
+
+	t.Run("cancel by last transformer", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		mapper := New().RegisterContextTransformer(stringToLabel, func(ctx context.Context, from reflect.Value, _ reflect.Type) (reflect.Value, error) {
+			cancel()
+			return reflect.ValueOf(Label(from.String())), nil
+		})
+
+		var to Label
+		assert.ErrorIs(t, mapper.From("a").CopyToContext(ctx, &to), context.Canceled)
+	})
+
+	t.Run("done context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := New().From(&AddressDTO{Zip: "abc"}).CopyToContext(ctx, new(AddressDTO))
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
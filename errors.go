@@ -0,0 +1,121 @@
+package structmapper
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// MappingError is error of mapping a field, with the field path from the source root e.g. `User.Addresses[2].Zip`
+type MappingError struct {
+	// Path of source field
+	Path string
+	// Source type
+	From reflect.Type
+	// Destination type
+	To reflect.Type
+	// Cause
+	Err error
+}
+
+// Error of error
+func (e *MappingError) Error() string {
+	if e.Path == "" {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%s: %v", e.Path, e.Err)
+}
+
+// Unwrap for errors.Is and errors.As
+func (e *MappingError) Unwrap() error {
+	return e.Err
+}
+
+// Cause for github.com/pkg/errors
+func (e *MappingError) Cause() error {
+	return e.Err
+}
+
+// MappingErrors is errors of all failing fields, returned by CollectErrors option
+type MappingErrors []*MappingError
+
+// Error of error
+func (e MappingErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+	return fmt.Sprintf("%d mapping errors: %s", len(e), strings.Join(messages, "; "))
+}
+
+// Unwrap for errors.Is and errors.As
+func (e MappingErrors) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, err := range e {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// fail returns err, or records it and returns nil to continue mapping if collecting errors
+func (c *copier) fail(err error) error {
+	var limitErr *LimitError
+	if err == nil || !c.collectErrors || c.ctx.Err() != nil || errors.As(err, &limitErr) {
+		// no more mapping once context is done or limit is exceeded
+		return err
+	}
+
+	var mappingErr *MappingError
+	if !errors.As(err, &mappingErr) {
+		mappingErr = &MappingError{Path: c.pathString(), Err: err}
+	}
+
+	c.logger.Errorf("%+v", mappingErr)
+	c.errs = append(c.errs, mappingErr)
+	return nil
+}
+
+// wrapError wraps err with current path, unless it is already MappingError
+func (c *copier) wrapError(err error, from reflect.Value, toType reflect.Type) error {
+	var mappingErr *MappingError
+	if errors.As(err, &mappingErr) {
+		return err
+	}
+
+	var fromType reflect.Type
+	if from.IsValid() {
+		fromType = from.Type()
+	}
+
+	return &MappingError{Path: c.pathString(), From: fromType, To: toType, Err: err}
+}
+
+// enter field or element
+func (c *copier) enter(segment string) {
+	c.path = append(c.path, segment)
+}
+
+// leave field or element
+func (c *copier) leave() {
+	c.path = c.path[:len(c.path)-1]
+}
+
+// pathString returns current path, followed by segments
+func (c *copier) pathString(segments ...string) string {
+	return strings.TrimPrefix(strings.Join(c.path, "")+strings.Join(segments, ""), ".")
+}
+
+func fieldSegment(name string) string {
+	return "." + name
+}
+
+func indexSegment(i int) string {
+	return "[" + strconv.Itoa(i) + "]"
+}
+
+func keySegment(key reflect.Value) string {
+	return fmt.Sprintf("[%v]", key)
+}
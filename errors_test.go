@@ -0,0 +1,147 @@
+package structmapper
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type AddressDTO struct {
+	Zip string `json:"zip"`
+}
+
+type UserDTO struct {
+	Addresses []AddressDTO           `json:"addresses"`
+	Tags      map[string]AddressDTO  `json:"tags"`
+	Extra     map[string]interface{} `json:"extra"`
+}
+
+type Address struct {
+	Zip int `json:"zip"`
+}
+
+type UserEntity struct {
+	Addresses []Address          `json:"addresses"`
+	Tags      map[string]Address `json:"tags"`
+	Extra     Address            `json:"extra"`
+}
+
+var errInvalidZip = errors.New("invalid zip")
+
+func TestMappingError(t *testing.T) {
+	mapper := New().RegisterTransformer(
+		Target{From: reflect.TypeOf(""), To: reflect.TypeOf(0)},
+		func(from reflect.Value, _ reflect.Type) (reflect.Value, error) {
+			if from.String() != "1000001" {
+				return reflect.Zero(reflect.TypeOf(0)), errInvalidZip
+			}
+			return reflect.ValueOf(1000001), nil
+		},
+	)
+
+	cases := []struct {
+		Name string
+		From interface{}
+		Path string
+	}{
+		{
+			Name: "slice element field",
+			From: &UserDTO{Addresses: []AddressDTO{{Zip: "1000001"}, {Zip: "1000001"}, {Zip: "abc"}}},
+			Path: "UserDTO.Addresses[2].Zip",
+		},
+		{
+			Name: "map value field",
+			From: &UserDTO{Tags: map[string]AddressDTO{"home": {Zip: "abc"}}},
+			Path: "UserDTO.Tags[home].Zip",
+		},
+		{
+			Name: "map to struct field",
+			From: &UserDTO{Extra: map[string]interface{}{"zip": "abc"}},
+			Path: "UserDTO.Extra.zip",
+		},
+	}
+
+	for _, _c := range cases {
+		c := _c
+		t.Run(c.Name, func(t *testing.T) {
+			err := mapper.From(c.From).CopyTo(new(UserEntity))
+
+			var mappingErr *MappingError
+			if assert.True(t, errors.As(err, &mappingErr)) {
+				assert.Equal(t, c.Path, mappingErr.Path)
+				assert.Equal(t, reflect.TypeOf(""), mappingErr.From)
+				assert.Equal(t, reflect.TypeOf(0), mappingErr.To)
+				assert.Equal(t, c.Path+": invalid zip", err.Error())
+			}
+			assert.True(t, errors.Is(err, errInvalidZip))
+			assert.Equal(t, errInvalidZip, errors.Cause(err))
+		})
+	}
+}
+
+func TestMappingErrorWithoutTransformer(t *testing.T) {
+	err := New().
+		From(&UserDTO{Addresses: []AddressDTO{{Zip: "abc"}}}).
+		CopyTo(new(UserEntity))
+
+	var mappingErr *MappingError
+	if assert.True(t, errors.As(err, &mappingErr)) {
+		assert.Equal(t, "UserDTO.Addresses[0].Zip", mappingErr.Path)
+		assert.EqualError(t, mappingErr, "UserDTO.Addresses[0].Zip: can't convert data abc -> int")
+	}
+}
+
+func TestCollectErrors(t *testing.T) {
+	from := &UserDTO{
+		Addresses: []AddressDTO{{Zip: "abc"}, {Zip: "1000001"}, {Zip: "xyz"}},
+		Tags:      map[string]AddressDTO{"home": {Zip: "abc"}, "work": {Zip: "1000001"}},
+		Extra:     map[string]interface{}{"zip": "1000001"},
+	}
+	to := new(UserEntity)
+
+	err := New().
+		RegisterTransformer(
+			Target{From: reflect.TypeOf(""), To: reflect.TypeOf(0)},
+			func(from reflect.Value, _ reflect.Type) (reflect.Value, error) {
+				if from.String() != "1000001" {
+					return reflect.Zero(reflect.TypeOf(0)), errInvalidZip
+				}
+				return reflect.ValueOf(1000001), nil
+			},
+		).
+		From(from).
+		CollectErrors().
+		CopyTo(to)
+
+	var mappingErrs MappingErrors
+	if assert.True(t, errors.As(err, &mappingErrs)) {
+		paths := make([]string, 0, len(mappingErrs))
+		for _, e := range mappingErrs {
+			paths = append(paths, e.Path)
+		}
+		assert.ElementsMatch(t, []string{
+			"UserDTO.Addresses[0].Zip",
+			"UserDTO.Addresses[2].Zip",
+			"UserDTO.Tags[home].Zip",
+		}, paths)
+	}
+	assert.True(t, errors.Is(err, errInvalidZip))
+
+	// successfully mapped fields are populated
+	assert.Equal(t, []Address{{}, {Zip: 1000001}, {}}, to.Addresses)
+	assert.Equal(t, map[string]Address{"home": {}, "work": {Zip: 1000001}}, to.Tags)
+	assert.Equal(t, Address{Zip: 1000001}, to.Extra)
+}
+
+func TestCollectErrorsWithoutError(t *testing.T) {
+	to := new(UserEntity)
+	err := New().
+		From(&UserDTO{Extra: map[string]interface{}{"zip": 1000001}}).
+		CollectErrors().
+		CopyTo(to)
+	if assert.NoError(t, err) {
+		assert.Equal(t, Address{Zip: 1000001}, to.Extra)
+	}
+}
@@ -55,3 +55,23 @@ func Example() {
 	// User &{ID:12345 Name:山田太郎 Age:32}
 	// Node &{Id:12345 Name:山田太郎 Description:32}
 }
+
+func ExampleMap() {
+	mapper := structmapper.RegisterFunc(structmapper.New(), func(age int32) (string, error) {
+		return strconv.FormatInt(int64(age), 10), nil
+	})
+
+	node, err := structmapper.Map[*User, *Node](mapper, &User{
+		ID:   "12345",
+		Name: "山田太郎",
+		Age:  32,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Printf("Node %+v\n", node)
+
+	// Output:
+	// Node &{Id:12345 Name:山田太郎 Description:32}
+}
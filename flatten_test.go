@@ -0,0 +1,105 @@
+package structmapper
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type OrderAddress struct {
+	City string `json:"city"`
+	Zip  string `json:"zip"`
+}
+
+type Customer struct {
+	Name    string        `json:"name"`
+	Address *OrderAddress `json:"address"`
+}
+
+type Order struct {
+	ID       string    `json:"id"`
+	Customer *Customer `json:"customer"`
+}
+
+type OrderRow struct {
+	ID           string `json:"id"`
+	CustomerName string `structmapper:"customer.name"`
+	CustomerCity string `structmapper:"customer.address.city"`
+	CustomerZip  int    `structmapper:"customer.address.zip"`
+}
+
+func TestCopyNestedPath(t *testing.T) {
+	zipTransformer := func(from reflect.Value, _ reflect.Type) (reflect.Value, error) {
+		if from.String() == "invalid" {
+			return reflect.Zero(reflect.TypeOf(0)), errInvalidZip
+		}
+		return reflect.ValueOf(len(from.String())), nil
+	}
+	mapper := New().RegisterTransformer(Target{From: reflect.TypeOf(""), To: reflect.TypeOf(0)}, zipTransformer)
+
+	t.Run("flatten", func(t *testing.T) {
+		from := &Order{
+			ID: "1",
+			Customer: &Customer{
+				Name:    "Taro",
+				Address: &OrderAddress{City: "Tokyo", Zip: "1000001"},
+			},
+		}
+		to := new(OrderRow)
+
+		if assert.NoError(t, mapper.From(from).Strict(StrictBoth).CopyTo(to)) {
+			assert.Equal(t, &OrderRow{ID: "1", CustomerName: "Taro", CustomerCity: "Tokyo", CustomerZip: 7}, to)
+		}
+	})
+
+	t.Run("flatten nil pointer", func(t *testing.T) {
+		to := new(OrderRow)
+
+		if assert.NoError(t, mapper.From(&Order{ID: "1", Customer: &Customer{Name: "Taro"}}).CopyTo(to)) {
+			assert.Equal(t, &OrderRow{ID: "1", CustomerName: "Taro"}, to)
+		}
+	})
+
+	t.Run("unflatten allocating pointers", func(t *testing.T) {
+		from := &OrderRow{ID: "1", CustomerName: "Taro", CustomerCity: "Tokyo"}
+		to := new(Order)
+
+		if assert.NoError(t, New().From(from).CopyTo(to)) && assert.NotNil(t, to.Customer) && assert.NotNil(t, to.Customer.Address) {
+			assert.Equal(t, "1", to.ID)
+			assert.Equal(t, "Taro", to.Customer.Name)
+			assert.Equal(t, "Tokyo", to.Customer.Address.City)
+		}
+	})
+
+	t.Run("merge into existing nested struct", func(t *testing.T) {
+		to := &Order{Customer: &Customer{Name: "Jiro", Address: &OrderAddress{Zip: "1000001"}}}
+
+		err := New().
+			From(&struct {
+				City string `structmapper:"customer.address.city"`
+			}{City: "Osaka"}).
+			Merge().
+			CopyTo(to)
+		if assert.NoError(t, err) {
+			assert.Equal(t, &Order{Customer: &Customer{Name: "Jiro", Address: &OrderAddress{City: "Osaka", Zip: "1000001"}}}, to)
+		}
+	})
+
+	t.Run("error path", func(t *testing.T) {
+		from := &Order{Customer: &Customer{Address: &OrderAddress{Zip: "invalid"}}}
+
+		err := mapper.From(from).CopyTo(new(OrderRow))
+		assert.EqualError(t, err, "Order.Customer.Address.Zip: invalid zip")
+	})
+
+	t.Run("unresolved path", func(t *testing.T) {
+		err := New().
+			From(&struct {
+				City string `structmapper:"customer.country"`
+			}{City: "Tokyo"}).
+			Strict(StrictSource).
+			CopyTo(new(Order))
+		assert.ErrorIs(t, err, ErrUnmappedField)
+	})
+}
@@ -0,0 +1,35 @@
+package structmapper
+
+import (
+	"github.com/pkg/errors"
+)
+
+// ErrFrozen is panicked on registration or configuration of frozen Mapper
+var ErrFrozen = errors.New("mapper is frozen")
+
+func (m *mapper) Freeze() Mapper {
+	frozen := &mapper{
+		transformerRepository: m.transformerRepository.Clone(),
+		frozen:                true,
+	}
+	frozen.config.Store(m.config.Load().clone())
+	m.resolvers.Range(func(key, value interface{}) bool {
+		frozen.resolvers.Store(key, value)
+		return true
+	})
+	m.profiles.Range(func(key, value interface{}) bool {
+		frozen.profiles.Store(key, value)
+		return true
+	})
+	m.hooks.Range(func(key, value interface{}) bool {
+		frozen.hooks.Store(key, value)
+		return true
+	})
+	return frozen
+}
+
+func (m *mapper) mustNotFrozen() {
+	if m.frozen {
+		panic(ErrFrozen)
+	}
+}
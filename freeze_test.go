@@ -0,0 +1,163 @@
+package structmapper
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type freezeFrom struct {
+	Age int32 `json:"age"`
+}
+
+type freezeTo struct {
+	Age string `json:"age"`
+}
+
+var int32ToStringTransformer = func(from reflect.Value, _ reflect.Type) (reflect.Value, error) {
+	return reflect.ValueOf(strconv.FormatInt(from.Int(), 10)), nil
+}
+
+func TestFreeze(t *testing.T) {
+	builder := New().RegisterTransformer(
+		Target{From: reflect.TypeOf(int32(0)), To: reflect.TypeOf("")},
+		int32ToStringTransformer,
+	)
+	frozen := builder.Freeze()
+
+	to := new(freezeTo)
+	if assert.NoError(t, frozen.From(&freezeFrom{Age: 65}).CopyTo(to)) {
+		assert.Equal(t, "65", to.Age)
+	}
+
+	assert.PanicsWithValue(t, ErrFrozen, func() {
+		frozen.RegisterTransformer(Target{}, int32ToStringTransformer)
+	})
+	assert.PanicsWithValue(t, ErrFrozen, func() {
+		frozen.RegisterResolver(reflect.TypeOf((*Shape)(nil)).Elem(), resolveShape)
+	})
+	assert.PanicsWithValue(t, ErrFrozen, func() {
+		frozen.Install(StringerModule)
+	})
+	assert.PanicsWithValue(t, ErrFrozen, func() {
+		frozen.EnableLogging()
+	})
+	assert.PanicsWithValue(t, ErrFrozen, func() {
+		frozen.Strict(StrictBoth)
+	})
+	assert.PanicsWithValue(t, ErrFrozen, func() {
+		frozen.MatchNames(CaseInsensitiveNames)
+	})
+	assert.PanicsWithValue(t, ErrFrozen, func() {
+		frozen.Limit(Limits{MaxDepth: 10})
+	})
+	assert.PanicsWithValue(t, ErrFrozen, func() {
+		frozen.RemoveTransformer(TransformerHandle{})
+	})
+	assert.PanicsWithValue(t, ErrFrozen, func() {
+		RegisterAfterMap(frozen, func(from *freezeFrom, to *freezeTo) error { return nil })
+	})
+
+	// later registration on builder does not affect frozen mapper
+	builder.RegisterTransformer(
+		Target{From: reflect.TypeOf(int32(0)), To: reflect.TypeOf("")},
+		func(from reflect.Value, _ reflect.Type) (reflect.Value, error) {
+			return reflect.ValueOf("overridden"), nil
+		},
+	)
+	to = new(freezeTo)
+	if assert.NoError(t, frozen.From(&freezeFrom{Age: 65}).CopyTo(to)) {
+		assert.Equal(t, "65", to.Age)
+	}
+}
+
+// run with -race
+func TestConcurrentRegistration(t *testing.T) {
+	mapper := New().RegisterResolver(reflect.TypeOf((*Shape)(nil)).Elem(), resolveShape)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			mapper.RegisterTransformerFunc(
+				func(target Target) bool {
+					return target.From.Name() == fmt.Sprintf("never%d", i)
+				},
+				int32ToStringTransformer,
+			)
+			mapper.RegisterResolver(reflect.TypeOf((*Shape)(nil)).Elem(), resolveShape)
+			mapper.MatchNames(CaseInsensitiveNames)
+			mapper.TagNames(defaultTagNames...)
+			mapper.Strict(StrictNone)
+			mapper.Limit(Limits{MaxDepth: 10})
+			mapper.RegisterProfile(CreateMap[freezeFrom, freezeTo]())
+			RegisterAfterMap(mapper, func(from *freezeFrom, to *freezeTo) error { return nil })
+		}(i)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				assert.NoError(t, mapper.From(&freezeFrom{Age: 65}).CopyTo(new(freezeTo)))
+				assert.NoError(t, mapper.From(&DrawingDTO{Main: &ShapeDTO{Kind: "circle"}}).CopyTo(new(Drawing)))
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// run with -race
+func TestConcurrentCopyOfFrozenMapper(t *testing.T) {
+	mapper := New().
+		RegisterTransformer(Target{From: reflect.TypeOf(int32(0)), To: reflect.TypeOf("")}, int32ToStringTransformer).
+		Freeze()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				to := new(freezeTo)
+				if assert.NoError(t, mapper.From(&freezeFrom{Age: 65}).CopyTo(to)) {
+					assert.Equal(t, "65", to.Age)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// a plan compiled by a copy running concurrently with registration must not be used after it
+func TestRegistrationDuringCopy(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		mapper := New()
+
+		done := make(chan struct{})
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+					assert.NoError(t, mapper.From(&freezeFrom{Age: 65}).CopyTo(new(freezeTo)))
+				}
+			}
+		}()
+
+		mapper.RegisterTransformer(Target{From: reflect.TypeOf(int32(0)), To: reflect.TypeOf("")}, int32ToStringTransformer)
+		to := new(freezeTo)
+		if assert.NoError(t, mapper.From(&freezeFrom{Age: 65}).CopyTo(to)) {
+			assert.Equal(t, "65", to.Age)
+		}
+
+		close(done)
+		wg.Wait()
+	}
+}
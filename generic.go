@@ -0,0 +1,110 @@
+package structmapper
+
+import (
+	"reflect"
+
+	"github.com/pkg/errors"
+)
+
+// ErrRoundTrip is error of CheckRoundTrip on value not mapped back to the same value
+var ErrRoundTrip = errors.New("round trip mismatch")
+
+// Map copies from to new To by m
+func Map[From, To any](m Mapper, from From) (To, error) {
+	var to To
+
+	fromValue := reflect.ValueOf(from)
+	if !fromValue.IsValid() || fromValue.Kind() == reflect.Ptr && fromValue.IsNil() {
+		return to, nil
+	}
+
+	if toType := typeOf[To](); toType.Kind() == reflect.Ptr {
+		// allocate pointer destination
+		to = reflect.New(toType.Elem()).Interface().(To)
+		err := m.From(from).CopyTo(to)
+		return to, err
+	}
+
+	err := m.From(from).CopyTo(&to)
+	return to, err
+}
+
+// MapSlice copies elements of from to new []To by m, nil for nil
+func MapSlice[From, To any](m Mapper, from []From) ([]To, error) {
+	var to []To
+	err := m.From(from).CopyTo(&to)
+	return to, err
+}
+
+// RegisterFunc registers fn as Transformer of From -> To.
+// From and To are matched as dereferenced types of source and destination if they're pointers,
+// and fn of pointer From is called with address of non-nil source value.
+// Pointer destination is left nil if fn of pointer To returns nil.
+func RegisterFunc[From, To any](m Mapper, fn func(From) (To, error)) Mapper {
+	target := Target{From: typeOf[From](), To: typeOf[To]()}
+	fromPtr := target.From.Kind() == reflect.Ptr
+	if fromPtr {
+		// source is dereferenced before conversion
+		target.From = target.From.Elem()
+	}
+	ptr := target.To.Kind() == reflect.Ptr
+	if ptr {
+		target.To = target.To.Elem()
+	}
+	if target.From.Kind() == reflect.Ptr || target.To.Kind() == reflect.Ptr {
+		panic(errors.Errorf("%+v has pointer to pointer", target))
+	}
+
+	transformer := func(from reflect.Value, toType reflect.Type) (reflect.Value, error) {
+		if fromPtr {
+			from = forceAddr(from)
+		}
+		to, err := fn(from.Interface().(From))
+		if err != nil {
+			return reflect.Zero(toType), err
+		}
+
+		v := reflect.ValueOf(&to).Elem()
+		if ptr && toType.Kind() != reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Zero(toType), nil
+			}
+			return v.Elem(), nil
+		}
+		return v, nil
+	}
+
+	if ptr {
+		// for pointer destination, which is left nil by nil result
+		m = m.RegisterTransformer(Target{From: target.From, To: typeOf[To]()}, transformer)
+	}
+	return m.RegisterTransformer(target, transformer)
+}
+
+// RegisterBidirectionalFunc registers forward as Transformer of A -> B, and backward of B -> A, as RegisterFunc does
+func RegisterBidirectionalFunc[A, B any](m Mapper, forward func(A) (B, error), backward func(B) (A, error)) Mapper {
+	return RegisterFunc(RegisterFunc(m, forward), backward)
+}
+
+// CheckRoundTrip maps each of values to B and back to A by m, and fails with ErrRoundTrip unless it equals the original value.
+// Use it in tests of Transformers registered by RegisterBidirectional, so that both directions don't drift.
+func CheckRoundTrip[A, B any](m Mapper, values ...A) error {
+	for _, value := range values {
+		b, err := Map[A, B](m, value)
+		if err != nil {
+			return err
+		}
+		back, err := Map[B, A](m, b)
+		if err != nil {
+			return err
+		}
+		if !reflect.DeepEqual(value, back) {
+			return errors.WithMessagef(ErrRoundTrip, "%+v is mapped back to %+v through %+v", value, back, typeOf[B]())
+		}
+	}
+	return nil
+}
+
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
@@ -0,0 +1,184 @@
+package structmapper
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/stretchr/testify/assert"
+)
+
+func parseZip(zip string) (int, error) {
+	n, err := strconv.Atoi(zip)
+	if err != nil {
+		return 0, errInvalidZip
+	}
+	return n, nil
+}
+
+func TestMap(t *testing.T) {
+	mapper := RegisterFunc(New(), parseZip)
+
+	t.Run("to value", func(t *testing.T) {
+		to, err := Map[*AddressDTO, Address](mapper, &AddressDTO{Zip: "1000001"})
+		if assert.NoError(t, err) {
+			assert.Equal(t, Address{Zip: 1000001}, to)
+		}
+	})
+
+	t.Run("to pointer", func(t *testing.T) {
+		to, err := Map[AddressDTO, *Address](mapper, AddressDTO{Zip: "1000001"})
+		if assert.NoError(t, err) {
+			assert.Equal(t, &Address{Zip: 1000001}, to)
+		}
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		to, err := Map[*AddressDTO, *Address](mapper, nil)
+		if assert.NoError(t, err) {
+			assert.Nil(t, to)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		_, err := Map[*AddressDTO, Address](mapper, &AddressDTO{Zip: "abc"})
+		assert.EqualError(t, err, "AddressDTO.Zip: invalid zip")
+	})
+}
+
+func TestMapSlice(t *testing.T) {
+	mapper := RegisterFunc(New(), parseZip)
+
+	t.Run("elements", func(t *testing.T) {
+		to, err := MapSlice[AddressDTO, *Address](mapper, []AddressDTO{{Zip: "1000001"}, {Zip: "1000002"}})
+		if assert.NoError(t, err) {
+			assert.Equal(t, []*Address{{Zip: 1000001}, {Zip: 1000002}}, to)
+		}
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		to, err := MapSlice[AddressDTO, Address](mapper, nil)
+		if assert.NoError(t, err) {
+			assert.Nil(t, to)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		_, err := MapSlice[AddressDTO, Address](mapper, []AddressDTO{{Zip: "1000001"}, {Zip: "abc"}})
+		assert.EqualError(t, err, "[1].Zip: invalid zip")
+	})
+}
+
+func TestRegisterFunc(t *testing.T) {
+	t.Run("pointer result", func(t *testing.T) {
+		mapper := RegisterFunc(New(), func(zip string) (*int, error) {
+			if zip == "" {
+				return nil, nil
+			}
+			n, err := parseZip(zip)
+			return &n, err
+		})
+
+		to := new(UserEntity)
+		err := mapper.From(&UserDTO{Addresses: []AddressDTO{{Zip: "1000001"}, {Zip: ""}}}).CopyTo(to)
+		if assert.NoError(t, err) {
+			assert.Equal(t, []Address{{Zip: 1000001}, {Zip: 0}}, to.Addresses)
+		}
+	})
+
+	t.Run("nil pointer result", func(t *testing.T) {
+		mapper := RegisterFunc(New(), func(zip string) (*int, error) {
+			if zip == "" {
+				return nil, nil
+			}
+			n, err := parseZip(zip)
+			return &n, err
+		})
+
+		type Address struct{ Zip *int }
+		to := new(struct{ Addresses []Address })
+		err := mapper.From(&UserDTO{Addresses: []AddressDTO{{Zip: "1000001"}, {Zip: ""}}}).CopyTo(to)
+		if assert.NoError(t, err) {
+			if assert.Len(t, to.Addresses, 2) && assert.NotNil(t, to.Addresses[0].Zip) {
+				assert.Equal(t, 1000001, *to.Addresses[0].Zip)
+			}
+			assert.Nil(t, to.Addresses[1].Zip)
+		}
+
+		var zips []*int
+		if assert.NoError(t, mapper.From([]string{"", "1000001"}).CopyTo(&zips)) && assert.Len(t, zips, 2) {
+			assert.Nil(t, zips[0])
+			assert.Equal(t, 1000001, *zips[1])
+		}
+	})
+
+	t.Run("frozen", func(t *testing.T) {
+		assert.PanicsWithValue(t, ErrFrozen, func() {
+			RegisterFunc(New().Freeze(), parseZip)
+		})
+	})
+}
+
+type Celsius float64
+
+type Fahrenheit float64
+
+func TestRegisterBidirectionalFunc(t *testing.T) {
+	mapper := RegisterBidirectionalFunc(New(),
+		func(c Celsius) (Fahrenheit, error) { return Fahrenheit(c*9/5 + 32), nil },
+		func(f Fahrenheit) (Celsius, error) { return Celsius((f - 32) * 5 / 9), nil },
+	)
+
+	f, err := Map[Celsius, Fahrenheit](mapper, 100)
+	if assert.NoError(t, err) {
+		assert.Equal(t, Fahrenheit(212), f)
+	}
+	c, err := Map[Fahrenheit, Celsius](mapper, 212)
+	if assert.NoError(t, err) {
+		assert.Equal(t, Celsius(100), c)
+	}
+}
+
+func TestCheckRoundTrip(t *testing.T) {
+	t.Run("protobuf", func(t *testing.T) {
+		mapper := New().Install(ProtobufModule)
+		now := time.Unix(1600000000, 123456789).UTC()
+
+		assert.NoError(t, CheckRoundTrip[time.Time, timestamp.Timestamp](mapper, now, time.Unix(0, 0).UTC()))
+		assert.NoError(t, CheckRoundTrip[string, timestamp.Timestamp](mapper, "2020-09-13T12:26:40Z"))
+	})
+
+	t.Run("mismatch", func(t *testing.T) {
+		mapper := RegisterBidirectionalFunc(New(),
+			func(c Celsius) (Fahrenheit, error) { return Fahrenheit(c*9/5 + 32), nil },
+			// drifted
+			func(f Fahrenheit) (Celsius, error) { return Celsius(f - 32), nil },
+		)
+
+		err := CheckRoundTrip[Celsius, Fahrenheit](mapper, 0, 100)
+		assert.ErrorIs(t, err, ErrRoundTrip)
+		assert.EqualError(t, err, "100 is mapped back to 180 through structmapper.Fahrenheit: round trip mismatch")
+	})
+}
+
+func TestRegisterFuncOfPointer(t *testing.T) {
+	mapper := RegisterFunc(New(), func(p *int) (string, error) {
+		return "#" + strconv.Itoa(*p), nil
+	})
+
+	v := 5
+	to := new(struct{ V string })
+	if assert.NoError(t, mapper.From(&struct{ V *int }{V: &v}).CopyTo(to)) {
+		assert.Equal(t, "#5", to.V)
+	}
+
+	var tags []string
+	if assert.NoError(t, mapper.From([]*int{&v}).CopyTo(&tags)) {
+		assert.Equal(t, []string{"#5"}, tags)
+	}
+
+	assert.Panics(t, func() {
+		RegisterFunc(New(), func(p **int) (string, error) { return "", nil })
+	})
+}
@@ -0,0 +1,103 @@
+package structmapper
+
+import (
+	"reflect"
+
+	"github.com/pkg/errors"
+)
+
+// BeforeMapper is implemented by destination struct to be called before its fields are copied
+type BeforeMapper interface {
+	// BeforeMap is called with pointer to source struct
+	BeforeMap(from interface{}) error
+}
+
+// AfterMapper is implemented by destination struct to be called after its fields are copied
+type AfterMapper interface {
+	// AfterMap is called with pointer to source struct
+	AfterMap(from interface{}) error
+}
+
+// MapHook is called with pointers to source and destination struct of registered Target
+type MapHook func(from, to reflect.Value) error
+
+type mapHooks struct {
+	before []MapHook
+	after  []MapHook
+}
+
+var (
+	beforeMapperType = reflect.TypeOf((*BeforeMapper)(nil)).Elem()
+	afterMapperType  = reflect.TypeOf((*AfterMapper)(nil)).Elem()
+)
+
+func (m *mapper) RegisterHooks(target Target, before, after MapHook) Mapper {
+	m.mustNotFrozen()
+	if target.From.Kind() != reflect.Struct || target.To.Kind() != reflect.Struct {
+		panic(errors.Errorf("%+v is not struct types", target))
+	}
+
+	m.hooksLock.Lock()
+	defer m.hooksLock.Unlock()
+
+	hooks := new(mapHooks)
+	if registered, ok := m.hooks.Load(target); ok {
+		*hooks = *registered.(*mapHooks)
+	}
+	if before != nil {
+		hooks.before = append(hooks.before[:len(hooks.before):len(hooks.before)], before)
+	}
+	if after != nil {
+		hooks.after = append(hooks.after[:len(hooks.after):len(hooks.after)], after)
+	}
+
+	m.hooks.Store(target, hooks)
+	m.configure(nil)
+	return m
+}
+
+// RegisterBeforeMap registers fn called before fields of From struct are copied to To struct
+func RegisterBeforeMap[From, To any](m Mapper, fn func(from *From, to *To) error) Mapper {
+	return m.RegisterHooks(Target{From: typeOf[From](), To: typeOf[To]()}, func(from, to reflect.Value) error {
+		return fn(from.Interface().(*From), to.Interface().(*To))
+	}, nil)
+}
+
+// RegisterAfterMap registers fn called after fields of From struct are copied to To struct
+func RegisterAfterMap[From, To any](m Mapper, fn func(from *From, to *To) error) Mapper {
+	return m.RegisterHooks(Target{From: typeOf[From](), To: typeOf[To]()}, nil, func(from, to reflect.Value) error {
+		return fn(from.Interface().(*From), to.Interface().(*To))
+	})
+}
+
+// hooksOf returns hooks of BeforeMapper and AfterMapper of destination, followed by registered ones
+func (m *mapper) hooksOf(fromType, toType reflect.Type) (before, after []MapHook) {
+	if reflect.PtrTo(toType).Implements(beforeMapperType) {
+		before = append(before, func(from, to reflect.Value) error {
+			return to.Interface().(BeforeMapper).BeforeMap(from.Interface())
+		})
+	}
+	if reflect.PtrTo(toType).Implements(afterMapperType) {
+		after = append(after, func(from, to reflect.Value) error {
+			return to.Interface().(AfterMapper).AfterMap(from.Interface())
+		})
+	}
+
+	if registered, ok := m.hooks.Load(Target{From: fromType, To: toType}); ok {
+		before = append(before, registered.(*mapHooks).before...)
+		after = append(after, registered.(*mapHooks).after...)
+	}
+	return before, after
+}
+
+// runHooks calls hooks with pointers to from and to
+func (c *copier) runHooks(hooks []MapHook, to, from reflect.Value) error {
+	for _, hook := range hooks {
+		if err := hook(forceAddr(from), forceAddr(to)); err != nil {
+			if err := c.fail(c.wrapError(err, from, to.Type())); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
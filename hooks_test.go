@@ -0,0 +1,140 @@
+package structmapper
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type LineItemDTO struct {
+	Price int
+	Count int
+}
+
+type LineItem struct {
+	Price int
+	Count int
+	// computed
+	Amount int
+	// called hooks
+	calls []string
+}
+
+func (i *LineItem) BeforeMap(from interface{}) error {
+	i.calls = append(i.calls, "before")
+	return nil
+}
+
+func (i *LineItem) AfterMap(from interface{}) error {
+	dto := from.(*LineItemDTO)
+	i.Amount = dto.Price * dto.Count
+	i.calls = append(i.calls, "after")
+	return nil
+}
+
+// records hooks and types of source
+type hookRecorder struct {
+	Name  string
+	calls []string
+}
+
+func (r *hookRecorder) BeforeMap(from interface{}) error {
+	r.calls = append(r.calls, fmt.Sprintf("before %T", from))
+	return nil
+}
+
+func (r *hookRecorder) AfterMap(from interface{}) error {
+	r.calls = append(r.calls, fmt.Sprintf("after %T", from))
+	return nil
+}
+
+var errInvalidCount = errors.New("invalid count")
+
+func TestHooks(t *testing.T) {
+	t.Run("interfaces of destination", func(t *testing.T) {
+		to := new(LineItem)
+		if assert.NoError(t, New().From(&LineItemDTO{Price: 100, Count: 3}).CopyTo(to)) {
+			assert.Equal(t, 300, to.Amount)
+			assert.Equal(t, []string{"before", "after"}, to.calls)
+		}
+	})
+
+	t.Run("registered hooks at every nesting level", func(t *testing.T) {
+		mapper := RegisterAfterMap(New(), func(from *LineItemDTO, to *LineItem) error {
+			to.calls = append(to.calls, "registered")
+			return nil
+		})
+
+		to := new(struct{ Items []*LineItem })
+		err := mapper.From(&struct{ Items []LineItemDTO }{Items: []LineItemDTO{{Price: 100, Count: 1}, {Price: 200, Count: 2}}}).CopyTo(to)
+		if assert.NoError(t, err) && assert.Len(t, to.Items, 2) {
+			assert.Equal(t, 400, to.Items[1].Amount)
+			assert.Equal(t, []string{"before", "after", "registered"}, to.Items[1].calls)
+		}
+	})
+
+	t.Run("convertible types", func(t *testing.T) {
+		type hkA struct{ Name string }
+		called := 0
+		mapper := RegisterAfterMap(New(), func(from *hkA, to *hkA) error {
+			called++
+			return nil
+		})
+
+		to := new(hkA)
+		if assert.NoError(t, mapper.From(&hkA{Name: "a"}).CopyTo(to)) {
+			assert.Equal(t, "a", to.Name)
+			assert.Equal(t, 1, called)
+		}
+
+		recorder := new(hookRecorder)
+		if assert.NoError(t, New().From(&struct{ Name string }{Name: "a"}).CopyTo(recorder)) {
+			assert.Equal(t, []string{"before *struct { Name string }", "after *struct { Name string }"}, recorder.calls)
+		}
+	})
+
+	t.Run("map to struct", func(t *testing.T) {
+		to := new(struct{ Items []*hookRecorder })
+		err := New().From(map[string]interface{}{"Items": []interface{}{map[string]interface{}{"Name": "a"}}}).CopyTo(to)
+		if assert.NoError(t, err) && assert.Len(t, to.Items, 1) {
+			assert.Equal(t, "a", to.Items[0].Name)
+			assert.Equal(t, []string{"before *map[string]interface {}", "after *map[string]interface {}"}, to.Items[0].calls)
+		}
+	})
+
+	t.Run("error path", func(t *testing.T) {
+		mapper := RegisterBeforeMap(New(), func(from *LineItemDTO, to *LineItem) error {
+			if from.Count < 0 {
+				return errInvalidCount
+			}
+			return nil
+		})
+
+		err := mapper.From(&struct{ Items []LineItemDTO }{Items: []LineItemDTO{{Count: 1}, {Count: -1}}}).CopyTo(new(struct{ Items []LineItem }))
+		assert.EqualError(t, err, "Items[1]: invalid count")
+		assert.ErrorIs(t, err, errInvalidCount)
+	})
+
+	t.Run("merge", func(t *testing.T) {
+		var called bool
+		mapper := New().RegisterHooks(Target{From: reflect.TypeOf(LineItemDTO{}), To: reflect.TypeOf(LineItem{})}, nil, func(from, to reflect.Value) error {
+			called = true
+			return nil
+		})
+
+		to := &LineItem{Price: 100}
+		if assert.NoError(t, mapper.From(&LineItemDTO{Count: 2}).Merge().Overwrite(SkipZero).CopyTo(to)) {
+			assert.True(t, called)
+			assert.Equal(t, 2, to.Count)
+		}
+	})
+
+	t.Run("not struct types", func(t *testing.T) {
+		assert.Panics(t, func() {
+			RegisterAfterMap(New(), func(from *int, to *string) error { return nil })
+		})
+	})
+}
@@ -0,0 +1,65 @@
+package structmapper
+
+import (
+	"reflect"
+
+	"github.com/pkg/errors"
+)
+
+// Resolver of concrete type to instantiate for interface typed destination, called with dereferenced source value
+type ConcreteTypeResolver func(from reflect.Value) (reflect.Type, error)
+
+func (m *mapper) RegisterResolver(interfaceType reflect.Type, resolver ConcreteTypeResolver) Mapper {
+	m.mustNotFrozen()
+	if interfaceType.Kind() != reflect.Interface {
+		panic(errors.Errorf("%+v is not interface type", interfaceType))
+	}
+
+	m.resolvers.Store(interfaceType, resolver)
+	m.configure(nil)
+	return m
+}
+
+// convertInterface assigns value implementing toType, or converts it to concrete type by registered resolver
+func (c *copier) convertInterface(from reflect.Value, toType reflect.Type) (reflect.Value, error) {
+	if !from.IsValid() || from.Kind() == reflect.Ptr && from.IsNil() {
+		return reflect.Zero(toType), nil
+	}
+
+	implements := from.Type().Implements(toType)
+	if implements || from.Kind() != reflect.Ptr && reflect.PtrTo(from.Type()).Implements(toType) {
+		// shared as it is, not checked by copying elements
+		if err := c.checkLimits(from); err != nil {
+			return reflect.Zero(toType), err
+		}
+		if !implements {
+			from = forceAddr(from)
+		}
+		return wrapInterface(from, toType), nil
+	}
+
+	from = indirect(from)
+	resolver, ok := c.resolvers.Load(toType)
+	if !ok {
+		return reflect.Zero(toType), errors.Errorf("can't convert data %+v -> %+v: no concrete type resolver", from, toType)
+	}
+
+	concreteType, err := resolver.(ConcreteTypeResolver)(from)
+	if err != nil {
+		return reflect.Zero(toType), err
+	}
+	if !concreteType.Implements(toType) {
+		return reflect.Zero(toType), errors.Errorf("can't convert data %+v -> %+v: resolved type %+v does not implement it", from, toType, concreteType)
+	}
+
+	c.logger.Printf("convertInterface(%+v -> %+v as %+v)", from.Type(), toType, concreteType)
+	v, err := c.convert(from, indirectType(concreteType))
+	if err != nil {
+		return reflect.Zero(toType), err
+	}
+	if concreteType.Kind() == reflect.Ptr {
+		v = forceAddr(v)
+	}
+
+	return wrapInterface(v, toType), nil
+}
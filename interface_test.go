@@ -0,0 +1,153 @@
+package structmapper
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type Shape interface {
+	Area() float64
+}
+
+type Circle struct {
+	Radius float64 `json:"radius"`
+}
+
+func (c *Circle) Area() float64 {
+	return math.Pi * c.Radius * c.Radius
+}
+
+type Square struct {
+	Side float64 `json:"side"`
+}
+
+func (s Square) Area() float64 {
+	return s.Side * s.Side
+}
+
+type ShapeDTO struct {
+	Kind   string  `json:"kind"`
+	Radius float64 `json:"radius"`
+	Side   float64 `json:"side"`
+}
+
+type Drawing struct {
+	Name   string      `json:"name"`
+	Main   Shape       `json:"main"`
+	Shapes []Shape     `json:"shapes"`
+	Extra  interface{} `json:"extra"`
+}
+
+type DrawingDTO struct {
+	Name   string      `json:"name"`
+	Main   *ShapeDTO   `json:"main"`
+	Shapes []ShapeDTO  `json:"shapes"`
+	Extra  interface{} `json:"extra"`
+}
+
+func resolveShape(from reflect.Value) (reflect.Type, error) {
+	dto, ok := from.Interface().(ShapeDTO)
+	if !ok {
+		return nil, errors.Errorf("unexpected shape %+v", from)
+	}
+
+	switch dto.Kind {
+	case "circle":
+		return reflect.TypeOf(&Circle{}), nil
+	case "square":
+		return reflect.TypeOf(Square{}), nil
+	default:
+		return nil, errors.Errorf("unknown shape kind %s", dto.Kind)
+	}
+}
+
+func TestCopyInterface(t *testing.T) {
+	t.Run("resolve concrete type of interface destination", func(t *testing.T) {
+		from := &DrawingDTO{
+			Name: "drawing",
+			Main: &ShapeDTO{Kind: "circle", Radius: 2},
+			Shapes: []ShapeDTO{
+				{Kind: "square", Side: 3},
+				{Kind: "circle", Radius: 1},
+			},
+			Extra: "extra",
+		}
+		to := new(Drawing)
+
+		err := New().
+			RegisterResolver(reflect.TypeOf((*Shape)(nil)).Elem(), resolveShape).
+			From(from).
+			CopyTo(to)
+		if assert.NoError(t, err) {
+			assert.Equal(t, &Drawing{
+				Name:   "drawing",
+				Main:   &Circle{Radius: 2},
+				Shapes: []Shape{Square{Side: 3}, &Circle{Radius: 1}},
+				Extra:  "extra",
+			}, to)
+		}
+	})
+
+	t.Run("nil source of interface destination", func(t *testing.T) {
+		to := new(Drawing)
+
+		err := New().
+			RegisterResolver(reflect.TypeOf((*Shape)(nil)).Elem(), resolveShape).
+			From(&DrawingDTO{Name: "drawing"}).
+			CopyTo(to)
+		if assert.NoError(t, err) {
+			assert.Equal(t, &Drawing{Name: "drawing"}, to)
+		}
+	})
+
+	t.Run("assign value implementing interface", func(t *testing.T) {
+		from := &struct {
+			Main Circle `json:"main"`
+		}{
+			Main: Circle{Radius: 2},
+		}
+		to := new(Drawing)
+
+		if assert.NoError(t, New().From(from).CopyTo(to)) {
+			assert.Equal(t, &Drawing{Main: &Circle{Radius: 2}}, to)
+		}
+	})
+
+	t.Run("unwrap dynamic value of interface source", func(t *testing.T) {
+		from := &struct {
+			Count interface{} `json:"count"`
+			Shape Shape       `json:"shape"`
+		}{
+			Count: int32(42),
+			Shape: &Circle{Radius: 2},
+		}
+		to := new(struct {
+			Count int64  `json:"count"`
+			Shape Circle `json:"shape"`
+		})
+
+		if assert.NoError(t, New().From(from).CopyTo(to)) {
+			assert.EqualValues(t, 42, to.Count)
+			assert.Equal(t, Circle{Radius: 2}, to.Shape)
+		}
+	})
+
+	t.Run("no resolver", func(t *testing.T) {
+		err := New().
+			From(&DrawingDTO{Main: &ShapeDTO{Kind: "circle"}}).
+			CopyTo(new(Drawing))
+		assert.Error(t, err)
+	})
+
+	t.Run("resolver error", func(t *testing.T) {
+		err := New().
+			RegisterResolver(reflect.TypeOf((*Shape)(nil)).Elem(), resolveShape).
+			From(&DrawingDTO{Main: &ShapeDTO{Kind: "triangle"}}).
+			CopyTo(new(Drawing))
+		assert.EqualError(t, err, "DrawingDTO.Main: unknown shape kind triangle")
+	})
+}
@@ -0,0 +1,153 @@
+package structmapper
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/pkg/errors"
+)
+
+// Limits of a copy against pathological input, zero for unlimited
+type Limits struct {
+	// Maximum depth of nested structs, slices, arrays and maps
+	MaxDepth int
+	// Maximum length of each slice, array or map
+	MaxLength int
+	// Maximum number of elements of all slices, arrays and maps in a copy
+	MaxElements int
+}
+
+var (
+	// ErrMaxDepth is cause of LimitError exceeding Limits.MaxDepth
+	ErrMaxDepth = errors.New("max depth exceeded")
+	// ErrMaxLength is cause of LimitError exceeding Limits.MaxLength
+	ErrMaxLength = errors.New("max length exceeded")
+	// ErrMaxElements is cause of LimitError exceeding Limits.MaxElements
+	ErrMaxElements = errors.New("max elements exceeded")
+)
+
+// LimitError is error of exceeding Limits, which aborts the copy even if collecting errors
+type LimitError struct {
+	// ErrMaxDepth, ErrMaxLength or ErrMaxElements
+	Err error
+	// Limit exceeded
+	Limit int
+}
+
+// Error of error
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("%v: limit %d", e.Err, e.Limit)
+}
+
+// Unwrap for errors.Is and errors.As
+func (e *LimitError) Unwrap() error {
+	return e.Err
+}
+
+func (m *mapper) Limit(limits Limits) Mapper {
+	m.mustNotFrozen()
+	m.configure(func(config *mapperConfig) {
+		config.options.limits = limits
+	})
+	return m
+}
+
+// checkDepth fails if current path is deeper than MaxDepth
+func (c *copier) checkDepth() error {
+	// root is not nested
+	if max := c.limits.MaxDepth; max > 0 && len(c.path)-1 > max {
+		return &LimitError{Err: ErrMaxDepth, Limit: max}
+	}
+	return nil
+}
+
+// countElements checks depth and length of slice, array or map from, and adds its elements to the copy
+func (c *copier) countElements(from reflect.Value) error {
+	if err := c.checkDepth(); err != nil {
+		return err
+	}
+
+	n := from.Len()
+	if max := c.limits.MaxLength; max > 0 && n > max {
+		return &LimitError{Err: ErrMaxLength, Limit: max}
+	}
+
+	c.elements += n
+	if max := c.limits.MaxElements; max > 0 && c.elements > max {
+		return &LimitError{Err: ErrMaxElements, Limit: max}
+	}
+	return nil
+}
+
+// checkLimits checks limits on from shared as it is by plain conversion, as if its exported fields and elements were copied.
+// Pointers already being walked are skipped, not to walk cycles.
+func (c *copier) checkLimits(from reflect.Value) error {
+	if c.limits == (Limits{}) {
+		return nil
+	}
+	return c.walkLimits(from, nil)
+}
+
+func (c *copier) walkLimits(from reflect.Value, walking []uintptr) error {
+	switch from.Kind() {
+	case reflect.Interface:
+		return c.walkLimits(from.Elem(), walking)
+
+	case reflect.Ptr:
+		if from.IsNil() {
+			return nil
+		}
+		for _, ptr := range walking {
+			if ptr == from.Pointer() {
+				return nil
+			}
+		}
+		return c.walkLimits(from.Elem(), append(walking, from.Pointer()))
+
+	case reflect.Struct:
+		if !hasExportedFields(from.Type()) {
+			return nil
+		}
+		if err := c.checkDepth(); err != nil {
+			return c.wrapError(err, from, from.Type())
+		}
+		for i := 0; i < from.NumField(); i++ {
+			if field := from.Type().Field(i); field.PkgPath == "" {
+				c.enter(fieldSegment(field.Name))
+				err := c.walkLimits(from.Field(i), walking)
+				c.leave()
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+	case reflect.Slice, reflect.Array:
+		if err := c.countElements(from); err != nil {
+			return c.wrapError(err, from, from.Type())
+		}
+		for i := 0; i < from.Len(); i++ {
+			c.enter(indexSegment(i))
+			err := c.walkLimits(from.Index(i), walking)
+			c.leave()
+			if err != nil {
+				return err
+			}
+		}
+
+	case reflect.Map:
+		if err := c.countElements(from); err != nil {
+			return c.wrapError(err, from, from.Type())
+		}
+		iter := from.MapRange()
+		for iter.Next() {
+			c.enter(keySegment(iter.Key()))
+			err := c.walkLimits(iter.Value(), walking)
+			c.leave()
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
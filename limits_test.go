@@ -0,0 +1,92 @@
+package structmapper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type LimitNode struct {
+	Name  string
+	Child *LimitNode
+}
+
+func nestedPayload(depth int) map[string]interface{} {
+	payload := map[string]interface{}{"Name": "leaf"}
+	for i := 0; i < depth; i++ {
+		payload = map[string]interface{}{"Name": "node", "Child": payload}
+	}
+	return payload
+}
+
+func TestLimits(t *testing.T) {
+	t.Run("max depth", func(t *testing.T) {
+		mapper := New().Limit(Limits{MaxDepth: 3})
+
+		assert.NoError(t, mapper.From(nestedPayload(3)).CopyTo(new(LimitNode)))
+
+		err := mapper.From(nestedPayload(4)).CopyTo(new(LimitNode))
+		assert.ErrorIs(t, err, ErrMaxDepth)
+		assert.EqualError(t, err, "Child.Child.Child.Child: max depth exceeded: limit 3")
+	})
+
+	t.Run("max length", func(t *testing.T) {
+		var to []int64
+		err := New().Limit(Limits{MaxLength: 3}).From([]int{1, 2, 3, 4}).CopyTo(&to)
+
+		var limitErr *LimitError
+		if assert.ErrorAs(t, err, &limitErr) {
+			assert.Equal(t, ErrMaxLength, limitErr.Err)
+			assert.Equal(t, 3, limitErr.Limit)
+		}
+	})
+
+	t.Run("max elements", func(t *testing.T) {
+		from := &struct {
+			A []int
+			B []int
+			C map[string]int
+		}{A: []int{1, 2, 3}, B: []int{4, 5, 6}, C: map[string]int{"a": 1}}
+		to := new(struct {
+			A []int64
+			B []int64
+			C map[string]int64
+		})
+
+		err := New().Limit(Limits{MaxLength: 3, MaxElements: 5}).From(from).CollectErrors().CopyTo(to)
+		assert.ErrorIs(t, err, ErrMaxElements)
+		assert.Nil(t, to.C)
+
+		assert.NoError(t, New().Limit(Limits{MaxElements: 7}).From(from).CopyTo(to))
+	})
+
+	t.Run("same type containers", func(t *testing.T) {
+		type Payload struct {
+			Extra map[string]interface{}
+			Tags  []string
+		}
+		mapper := New().Limit(Limits{MaxDepth: 3, MaxLength: 10, MaxElements: 20})
+
+		assert.NoError(t, mapper.From(&Payload{Extra: nestedPayload(1), Tags: []string{"a", "b"}}).CopyTo(new(Payload)))
+
+		err := mapper.From(&Payload{Extra: nestedPayload(50)}).CopyTo(new(Payload))
+		assert.ErrorIs(t, err, ErrMaxDepth)
+		assert.EqualError(t, err, "Payload.Extra[Child][Child][Child]: max depth exceeded: limit 3")
+
+		err = mapper.From(&Payload{Tags: make([]string, 100)}).CopyTo(new(Payload))
+		assert.ErrorIs(t, err, ErrMaxLength)
+		assert.EqualError(t, err, "Payload.Tags: max length exceeded: limit 10")
+
+		var to []string
+		err = mapper.From(make([]string, 100)).CopyTo(&to)
+		assert.ErrorIs(t, err, ErrMaxLength)
+	})
+
+	t.Run("shared as interface", func(t *testing.T) {
+		to := new(struct{ Extra interface{} })
+		err := New().Limit(Limits{MaxElements: 20}).
+			From(&struct{ Extra []int }{Extra: make([]int, 30)}).
+			CopyTo(to)
+		assert.ErrorIs(t, err, ErrMaxElements)
+	})
+}
@@ -0,0 +1,276 @@
+package structmapper
+
+import (
+	"reflect"
+)
+
+// convertMapToStruct copies map entries to struct fields. Keys are resolved by `structmapper` tag, `json` tag, or field name.
+func (c *copier) convertMapToStruct(from reflect.Value, toType reflect.Type) (reflect.Value, error) {
+	to := reflect.New(toType).Elem()
+	err := c.copyMapToStruct(to, from)
+	return to, err
+}
+
+// copyMapToStruct copies map entries to existing struct
+func (c *copier) copyMapToStruct(to, from reflect.Value) error {
+	if from.IsNil() {
+		return nil
+	}
+	if err := c.countElements(from); err != nil {
+		return err
+	}
+
+	keyType := from.Type().Key()
+	mappedKeys := make(map[string]struct{})
+
+	info := c.structInfoOf(to.Type())
+	if info.err != nil {
+		return info.err
+	}
+
+	// BeforeMapper and AfterMapper of destination, called with pointer to map
+	before, after := c.hooksOf(from.Type(), to.Type())
+	if err := c.runHooks(before, to, from); err != nil {
+		return err
+	}
+
+	for i := range info.fields {
+		if err := c.ctx.Err(); err != nil {
+			return err
+		}
+		toField := &info.fields[i]
+		if toField.PkgPath != "" || toField.tag.ReadOnly {
+			// unexported or not to write
+			continue
+		}
+
+		mapped := false
+		for _, name := range toField.names {
+			fromValue := from.MapIndex(reflect.ValueOf(name).Convert(keyType))
+			if !fromValue.IsValid() {
+				continue
+			}
+
+			mapped = true
+			mappedKeys[name] = struct{}{}
+
+			if overwritePolicyOf(c.overwrite, toField.StructField).Skips(fromValue) {
+				c.logger.Printf("skip(%s:%+v -> %s)", name, fromValue.Kind(), toField.Name)
+				break
+			}
+
+			toValue := fieldByIndexAlloc(to, toField.Index)
+			if !toValue.IsValid() || !toValue.CanSet() {
+				break
+			}
+
+			c.logger.Printf("copyValue(%s:%+v -> %s:%+v)", name, fromValue.Kind(), toField.Name, toValue.Kind())
+			c.enter(fieldSegment(name))
+			err := c.copyField(toValue, fromValue, toField.tag.String)
+			c.leave()
+			if err := c.fail(err); err != nil {
+				return err
+			}
+			break
+		}
+
+		if !mapped && (c.strict&StrictDestination != 0 || toField.tag.Required) && !toField.ignored {
+			if err := c.fail(c.unmappedDestinationError(from.Type(), toField.StructField)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if c.strict&StrictSource != 0 {
+		iter := from.MapRange()
+		for iter.Next() {
+			if _, ok := mappedKeys[iter.Key().String()]; !ok {
+				if err := c.fail(c.unmappedKeyError(iter.Key(), to.Type())); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return c.runHooks(after, to, from)
+}
+
+// convertStructToMap copies struct fields to new map
+func (c *copier) convertStructToMap(from reflect.Value, toType reflect.Type) (reflect.Value, error) {
+	to := reflect.MakeMap(toType)
+	err := c.copyStructToMap(to, from)
+	return to, err
+}
+
+// copyStructToMap copies struct fields to entries of existing map keyed by the first name of namesOf
+func (c *copier) copyStructToMap(to, from reflect.Value) error {
+	keyType := to.Type().Key()
+	elemType := to.Type().Elem()
+
+	info := c.structInfoOf(from.Type())
+	if info.err != nil {
+		return info.err
+	}
+
+	if err := c.checkDepth(); err != nil {
+		return err
+	}
+	if visited, err := c.visit(from, to.Type()); err != nil {
+		return err
+	} else if visited {
+		defer c.leaveVisit()
+	}
+	for i := range info.fields {
+		if err := c.ctx.Err(); err != nil {
+			return err
+		}
+		fromField := &info.fields[i]
+		if fromField.PkgPath != "" || fromField.tag.WriteOnly || len(fromField.names) == 0 {
+			// unexported, not to read, or excluded by `-`
+			continue
+		}
+
+		fromValue := fieldByIndex(from, fromField.Index)
+		if !fromValue.IsValid() {
+			continue
+		}
+		if overwritePolicyOf(c.overwrite, fromField.StructField).Skips(fromValue) {
+			c.logger.Printf("skip(%s:%+v -> %s)", fromField.Name, fromValue.Kind(), fromField.names[0])
+			continue
+		}
+		if fromField.tag.String {
+			fromValue, _ = convertByString(fromValue, stringType)
+		}
+
+		name := fromField.names[0]
+		c.logger.Printf("convertStructToMap[%s](%+v -> %+v)", name, fromValue.Kind(), elemType)
+
+		var (
+			elem reflect.Value
+			err  error
+		)
+		c.enter(fieldSegment(fromField.Name))
+		if elemType.Kind() == reflect.Interface && elemType.NumMethod() == 0 {
+			elem, err = c.convertToGeneric(fromValue, elemType)
+		} else {
+			elem, err = c.convertElem(fromValue, elemType)
+		}
+		c.leave()
+		if err != nil {
+			if err := c.fail(err); err != nil {
+				return err
+			}
+			continue
+		}
+
+		to.SetMapIndex(reflect.ValueOf(name).Convert(keyType), elem)
+	}
+
+	return nil
+}
+
+// convertToGeneric converts value to generic representation like encoding/json:
+// struct to map[string]interface{}, slice to []interface{}, pointer to its element, and nil to nil.
+func (c *copier) convertToGeneric(from reflect.Value, toType reflect.Type) (reflect.Value, error) {
+	if !from.IsValid() {
+		return reflect.Zero(toType), nil
+	}
+
+	if transformer := c.transformerRepository.Get(Target{To: toType, From: from.Type()}); transformer != nil {
+		v, err := transformer(c.ctx, from, toType)
+		if err != nil {
+			return v, c.wrapError(err, from, toType)
+		}
+		return v, nil
+	}
+
+	switch from.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if from.IsNil() {
+			return reflect.Zero(toType), nil
+		}
+		return c.convertToGeneric(from.Elem(), toType)
+
+	case reflect.Struct:
+		if !hasExportedFields(from.Type()) {
+			// opaque value e.g. time.Time
+			break
+		}
+		v, err := c.convertStructToMap(from, genericMapType)
+		return wrapInterface(v, toType), err
+
+	case reflect.Map:
+		if from.IsNil() {
+			return reflect.Zero(toType), nil
+		}
+		if from.Type().Key().Kind() != reflect.String {
+			break
+		}
+		if err := c.countElements(from); err != nil {
+			return reflect.Zero(toType), c.wrapError(err, from, toType)
+		}
+		to := reflect.MakeMapWithSize(genericMapType, from.Len())
+		iter := from.MapRange()
+		for iter.Next() {
+			if err := c.ctx.Err(); err != nil {
+				return wrapInterface(to, toType), err
+			}
+			c.enter(keySegment(iter.Key()))
+			elem, err := c.convertToGeneric(iter.Value(), toType)
+			c.leave()
+			if err != nil {
+				return wrapInterface(to, toType), err
+			}
+			to.SetMapIndex(iter.Key().Convert(stringType), elem)
+		}
+		return wrapInterface(to, toType), nil
+
+	case reflect.Slice:
+		if from.IsNil() {
+			return reflect.Zero(toType), nil
+		}
+		if from.Type().Elem().Kind() == reflect.Uint8 {
+			// []byte
+			break
+		}
+		if err := c.countElements(from); err != nil {
+			return reflect.Zero(toType), c.wrapError(err, from, toType)
+		}
+		to := reflect.MakeSlice(genericSliceType, 0, from.Len())
+		for i := 0; i < from.Len(); i++ {
+			if err := c.ctx.Err(); err != nil {
+				return wrapInterface(to, toType), err
+			}
+			c.enter(indexSegment(i))
+			elem, err := c.convertToGeneric(from.Index(i), toType)
+			c.leave()
+			if err != nil {
+				return wrapInterface(to, toType), err
+			}
+			to = reflect.Append(to, elem)
+		}
+		return wrapInterface(to, toType), nil
+	}
+
+	return wrapInterface(from, toType), nil
+}
+
+func wrapInterface(v reflect.Value, toType reflect.Type) reflect.Value {
+	to := reflect.New(toType).Elem()
+	to.Set(v)
+	return to
+}
+
+func hasExportedFields(t reflect.Type) bool {
+	for _, field := range deepFields(t) {
+		if field.PkgPath == "" {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	genericMapType   = reflect.TypeOf(map[string]interface{}(nil))
+	genericSliceType = reflect.TypeOf([]interface{}(nil))
+)
@@ -0,0 +1,104 @@
+package structmapper
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+// NameMatcher normalizes field names, and names of the same key match each other.
+// Set by Mapper.MatchNames, tried in order when no field has exactly the same name.
+type NameMatcher interface {
+	// Key of name
+	Key(name string) string
+}
+
+// NameMatcher func
+type NameMatcherFunc func(name string) string
+
+// Key of NameMatcher
+func (f NameMatcherFunc) Key(name string) string {
+	return f(name)
+}
+
+var (
+	// CaseInsensitiveNames matches names ignoring case, like `Id` and `ID`
+	CaseInsensitiveNames NameMatcher = NameMatcherFunc(strings.ToLower)
+
+	// SnakeCamelNames matches snake_case and CamelCase names by words, like `created_at`, `CreatedAt` and `createdAt`.
+	// Each upper case letter begins a word, so `UserID` doesn't match `user_id`.
+	SnakeCamelNames NameMatcher = NameMatcherFunc(func(name string) string {
+		return strings.Join(splitWords(name, false), "_")
+	})
+
+	// AcronymNames matches names as SnakeCamelNames does, reading a run of upper case letters as a word,
+	// like `UserID`, `UserId` and `user_id`, or `URLPath` and `UrlPath`.
+	AcronymNames NameMatcher = NameMatcherFunc(func(name string) string {
+		return strings.Join(splitWords(name, true), "_")
+	})
+)
+
+// ErrAmbiguousField is error of NameMatcher matching more than one field
+var ErrAmbiguousField = errors.New("ambiguous field")
+
+func (m *mapper) MatchNames(matchers ...NameMatcher) Mapper {
+	m.mustNotFrozen()
+	m.configure(func(config *mapperConfig) {
+		config.nameMatchers = append([]NameMatcher(nil), matchers...)
+	})
+	return m
+}
+
+// asKeysToFieldsMap returns exported fields by keys of their names
+func asKeysToFieldsMap(fields []fieldInfo, matcher NameMatcher) map[string][]*fieldInfo {
+	m := make(map[string][]*fieldInfo)
+	for i := range fields {
+		if fields[i].PkgPath != "" {
+			continue
+		}
+
+	names:
+		for _, name := range fields[i].names {
+			key := matcher.Key(name)
+			for _, f := range m[key] {
+				if f == &fields[i] {
+					continue names
+				}
+			}
+			m[key] = append(m[key], &fields[i])
+		}
+	}
+	return m
+}
+
+// splitWords splits name into lower case words by `_`, `-`, spaces and upper case letters
+func splitWords(name string, acronyms bool) []string {
+	var words []string
+	runes := []rune(name)
+	start := 0
+	flush := func(end int) {
+		if end > start {
+			words = append(words, strings.ToLower(string(runes[start:end])))
+		}
+		start = end
+	}
+
+	for i, r := range runes {
+		switch {
+		case r == '_' || r == '-' || unicode.IsSpace(r):
+			flush(i)
+			start = i + 1
+		case unicode.IsUpper(r) && i > start:
+			if !acronyms || !unicode.IsUpper(runes[i-1]) {
+				flush(i)
+			} else if i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+				// last letter of acronym begins next word, like `P` of `URLPath`
+				flush(i)
+			}
+		}
+	}
+	flush(len(runes))
+
+	return words
+}
@@ -0,0 +1,105 @@
+package structmapper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type AccountRecord struct {
+	UserId     string
+	AvatarUrl  string
+	CreateTime string `json:"created_at"`
+}
+
+type Account struct {
+	UserID    string
+	AvatarURL string
+	CreatedAt string
+}
+
+func TestMatchNames(t *testing.T) {
+	from := &AccountRecord{UserId: "u1", AvatarUrl: "https://example.com/u1.png", CreateTime: "2020-01-01"}
+
+	t.Run("exact names only by default", func(t *testing.T) {
+		to := new(Account)
+		if assert.NoError(t, New().From(from).CopyTo(to)) {
+			assert.Equal(t, &Account{}, to)
+		}
+	})
+
+	t.Run("case insensitive", func(t *testing.T) {
+		to := new(Account)
+		if assert.NoError(t, New().MatchNames(CaseInsensitiveNames).From(from).CopyTo(to)) {
+			assert.Equal(t, &Account{UserID: "u1", AvatarURL: "https://example.com/u1.png"}, to)
+		}
+	})
+
+	t.Run("snake and camel case", func(t *testing.T) {
+		to := new(Account)
+		if assert.NoError(t, New().MatchNames(SnakeCamelNames).From(from).CopyTo(to)) {
+			assert.Equal(t, &Account{CreatedAt: "2020-01-01"}, to)
+		}
+	})
+
+	t.Run("acronyms", func(t *testing.T) {
+		to := new(Account)
+		if assert.NoError(t, New().MatchNames(AcronymNames).From(from).Strict(StrictBoth).CopyTo(to)) {
+			assert.Equal(t, &Account{UserID: "u1", AvatarURL: "https://example.com/u1.png", CreatedAt: "2020-01-01"}, to)
+		}
+	})
+
+	t.Run("exact name first", func(t *testing.T) {
+		to := new(Account)
+		err := New().
+			MatchNames(CaseInsensitiveNames).
+			From(&struct {
+				Userid string
+				UserID string
+			}{Userid: "normalized", UserID: "exact"}).
+			CopyTo(to)
+		if assert.NoError(t, err) {
+			assert.Equal(t, "exact", to.UserID)
+		}
+	})
+
+	t.Run("ambiguous destination fields", func(t *testing.T) {
+		err := New().
+			MatchNames(AcronymNames).
+			From(&struct{ User_id string }{User_id: "u1"}).
+			CopyTo(new(struct {
+				UserID string
+				UserId string
+			}))
+		assert.ErrorIs(t, err, ErrAmbiguousField)
+	})
+
+	t.Run("ambiguous source fields", func(t *testing.T) {
+		err := New().
+			MatchNames(CaseInsensitiveNames).
+			From(&struct {
+				USERID string
+				UserId string
+			}{USERID: "u1", UserId: "u2"}).
+			CopyTo(new(Account))
+		assert.ErrorIs(t, err, ErrAmbiguousField)
+	})
+}
+
+func TestNameMatcherKey(t *testing.T) {
+	for name, key := range map[string]string{
+		"UserID":   "user_id",
+		"UserId":   "user_id",
+		"user_id":  "user_id",
+		"URLPath":  "url_path",
+		"UrlPath":  "url_path",
+		"userURL":  "user_url",
+		"ID":       "id",
+		"tag-name": "tag_name",
+	} {
+		assert.Equal(t, key, AcronymNames.Key(name), name)
+	}
+
+	assert.Equal(t, "user_i_d", SnakeCamelNames.Key("UserID"))
+	assert.Equal(t, "created_at", SnakeCamelNames.Key("createdAt"))
+}
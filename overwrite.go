@@ -0,0 +1,58 @@
+package structmapper
+
+import (
+	"reflect"
+)
+
+// Policy of overwriting destination field
+type OverwritePolicy int
+
+const (
+	// Always overwrite destination field (default)
+	OverwriteAlways OverwritePolicy = iota
+	// Skip nil pointer, slice, map or interface source field
+	SkipNil
+	// Skip zero value source field
+	SkipZero
+)
+
+// Skips reports whether source value v should leave destination untouched
+func (p OverwritePolicy) Skips(v reflect.Value) bool {
+	if v.Kind() == reflect.Interface && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	switch p {
+	case SkipNil:
+		return isNil(v)
+	case SkipZero:
+		return !v.IsValid() || v.IsZero()
+	default:
+		return false
+	}
+}
+
+// overwritePolicyOf returns the strongest of policy and `omitnil`, `omitempty` options of `structmapper` tag of fields
+func overwritePolicyOf(policy OverwritePolicy, fields ...reflect.StructField) OverwritePolicy {
+	for _, field := range fields {
+		if tag := tagOf(field); tag.OmitEmpty && policy < SkipZero {
+			policy = SkipZero
+		} else if tag.OmitNil && policy < SkipNil {
+			policy = SkipNil
+		}
+	}
+	return policy
+}
+
+func isNil(v reflect.Value) bool {
+	if !v.IsValid() {
+		return true
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface, reflect.Func, reflect.Chan:
+		return v.IsNil()
+	default:
+		return false
+	}
+}
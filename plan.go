@@ -0,0 +1,339 @@
+package structmapper
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Fields of struct type with resolved names, cached per type
+type structInfo struct {
+	typ    reflect.Type
+	fields []fieldInfo
+	byName map[string]*fieldInfo
+	// exported fields by keys of NameMatchers of mapper in order
+	byKey []map[string][]*fieldInfo
+	// invalid tag
+	err error
+}
+
+type fieldInfo struct {
+	reflect.StructField
+	// parsed `structmapper` tag
+	tag Tag
+	// names by tags of mapper, and field name
+	names []string
+	// out of strict mode
+	ignored bool
+}
+
+// Compiled mapping of struct to struct, cached per type pair
+type structPlan struct {
+	fields []fieldPlan
+	// fields to report in strict mode
+	unmappedFrom []*fieldInfo
+	unmappedTo   []*fieldInfo
+	// hooks of destination and registered
+	before []MapHook
+	after  []MapHook
+}
+
+type fieldPlan struct {
+	// nil if computed
+	from *fieldInfo
+	to   *fieldInfo
+	// segment of path, source field name or destination field name if computed
+	name string
+	// value of Compute configured by profile
+	compute func(from reflect.Value) reflect.Value
+	// policy by `omitempty`, `omitnil` tag options
+	policy OverwritePolicy
+	// assign or convert basic value without transformer
+	direct bool
+	// by `string` tag option
+	stringify bool
+}
+
+func (c *copier) structInfoOf(t reflect.Type) *structInfo {
+	if cached, ok := c.structInfos.Load(t); ok {
+		return cached.(*structInfo)
+	}
+
+	fields := deepFields(t)
+	info := &structInfo{typ: t, fields: make([]fieldInfo, 0, len(fields))}
+	for _, field := range fields {
+		tag, err := ParseTag(field.Tag.Get("structmapper"))
+		if err == nil && tag.Inline {
+			err = errors.Errorf("inline field of %+v is not struct", field.Type)
+		}
+		if err != nil && info.err == nil {
+			info.err = errors.WithMessagef(err, "%+v.%s", t, field.Name)
+		}
+
+		names := namesOf(field, c.tagNames)
+		info.fields = append(info.fields, fieldInfo{
+			StructField: field,
+			tag:         tag,
+			names:       names,
+			ignored:     isIgnored(field, names),
+		})
+	}
+	info.byName = asNamesToFieldMap(info.fields)
+	for _, matcher := range c.nameMatchers {
+		info.byKey = append(info.byKey, asKeysToFieldsMap(info.fields, matcher))
+	}
+
+	cached, _ := c.structInfos.LoadOrStore(t, info)
+	return cached.(*structInfo)
+}
+
+func (c *copier) structPlanOf(fromType, toType reflect.Type) (*structPlan, error) {
+	target := Target{From: fromType, To: toType}
+	if cached, ok := c.structPlans.Load(target); ok {
+		return cached.(*structPlan), nil
+	}
+
+	plan, err := c.compileStructPlan(fromType, toType)
+	if err != nil {
+		return nil, err
+	}
+
+	c.structPlans.Store(target, plan)
+	return plan, nil
+}
+
+func (c *copier) compileStructPlan(fromType, toType reflect.Type) (*structPlan, error) {
+	fromInfo := c.structInfoOf(fromType)
+	toInfo := c.structInfoOf(toType)
+	if fromInfo.err != nil {
+		return nil, fromInfo.err
+	} else if toInfo.err != nil {
+		return nil, toInfo.err
+	}
+	plan := new(structPlan)
+	plan.before, plan.after = c.hooksOf(fromType, toType)
+
+	// Map from field to field
+	copied := make(map[*fieldInfo]struct{})
+	// Source fields configured by profile
+	configured := make(map[*fieldInfo]struct{})
+	// Source fields mapped to any destination
+	mapped := make(map[*fieldInfo]struct{})
+
+	if p, ok := c.profiles.Load(Target{From: fromType, To: toType}); ok {
+		var err error
+		if copied, configured, err = c.applyProfile(plan, p.(*profile), fromInfo, toInfo); err != nil {
+			return nil, err
+		}
+	}
+
+	for i := range fromInfo.fields {
+		fromField := &fromInfo.fields[i]
+		if fromField.PkgPath != "" || fromField.tag.WriteOnly {
+			// unexported or not to read
+			continue
+		}
+		if _, ok := configured[fromField]; ok {
+			mapped[fromField] = struct{}{}
+			continue
+		}
+
+		for _, name := range fromField.names {
+			if toField, found := toInfo.byName[name]; found {
+				// has field
+				mapped[fromField] = struct{}{}
+				if _, ok := copied[toField]; !ok {
+					c.addFieldPlan(plan, fromField, toField)
+					copied[toField] = struct{}{}
+				}
+			} else if toPath, head := c.fieldPathOf(toType, name); toPath != nil {
+				// unflatten into nested destination field
+				mapped[fromField] = struct{}{}
+				c.addFieldPlan(plan, fromField, toPath)
+				copied[head] = struct{}{}
+			}
+		}
+	}
+
+	if err := c.matchNormalizedNames(plan, fromInfo, toInfo, copied, mapped, configured); err != nil {
+		return nil, err
+	}
+
+	for i := range toInfo.fields {
+		toField := &toInfo.fields[i]
+		if _, ok := copied[toField]; ok || toField.PkgPath != "" {
+			continue
+		}
+
+		for _, name := range toField.names {
+			if fromPath, head := c.fieldPathOf(fromType, name); fromPath != nil {
+				// flatten nested source field
+				c.addFieldPlan(plan, fromPath, toField)
+				copied[toField] = struct{}{}
+				mapped[head] = struct{}{}
+				break
+			}
+		}
+	}
+
+	for i := range fromInfo.fields {
+		fromField := &fromInfo.fields[i]
+		if _, ok := mapped[fromField]; !ok && !fromField.ignored && !fromField.tag.WriteOnly {
+			plan.unmappedFrom = append(plan.unmappedFrom, fromField)
+		}
+	}
+
+	for i := range toInfo.fields {
+		toField := &toInfo.fields[i]
+		if _, ok := copied[toField]; !ok && !toField.ignored && !toField.tag.ReadOnly {
+			plan.unmappedTo = append(plan.unmappedTo, toField)
+		}
+	}
+
+	return plan, nil
+}
+
+// matchNormalizedNames adds fields matched by NameMatchers to plan, after exact names.
+// A key matching two destination fields, or two source fields into a destination field, is ErrAmbiguousField.
+func (c *copier) matchNormalizedNames(plan *structPlan, fromInfo, toInfo *structInfo, copied, mapped, configured map[*fieldInfo]struct{}) error {
+	for i, matcher := range c.nameMatchers {
+		// source field matched to destination field by this matcher
+		matchedBy := make(map[*fieldInfo]*fieldInfo)
+
+		for j := range fromInfo.fields {
+			fromField := &fromInfo.fields[j]
+			if _, ok := mapped[fromField]; ok || fromField.PkgPath != "" || fromField.tag.WriteOnly {
+				continue
+			}
+			if _, ok := configured[fromField]; ok {
+				continue
+			}
+
+			for _, name := range fromField.names {
+				toFields := toInfo.byKey[i][matcher.Key(name)]
+				if len(toFields) == 0 {
+					continue
+				}
+				if len(toFields) > 1 {
+					return errors.WithMessagef(ErrAmbiguousField, "%s of %+v matches %s and %s of %+v",
+						fromField.Name, fromInfo.typ, toFields[0].Name, toFields[1].Name, toInfo.typ)
+				}
+
+				toField := toFields[0]
+				if other, ok := matchedBy[toField]; ok && other != fromField {
+					return errors.WithMessagef(ErrAmbiguousField, "%s and %s of %+v match %s of %+v",
+						other.Name, fromField.Name, fromInfo.typ, toField.Name, toInfo.typ)
+				}
+				if _, ok := copied[toField]; ok {
+					// matched by exact name or prior matcher
+					continue
+				}
+
+				c.addFieldPlan(plan, fromField, toField)
+				copied[toField] = struct{}{}
+				mapped[fromField] = struct{}{}
+				matchedBy[toField] = fromField
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// fieldPathOf resolves dotted path of field names like `customer.address.city` in struct t.
+// It returns nested field of the path with index from t and dotted Name, and field of the first name.
+func (c *copier) fieldPathOf(t reflect.Type, path string) (field *fieldInfo, head *fieldInfo) {
+	if !strings.Contains(path, ".") {
+		return nil, nil
+	}
+
+	var index []int
+	var names []string
+	for _, name := range strings.Split(path, ".") {
+		if t = indirectType(t); t.Kind() != reflect.Struct {
+			return nil, nil
+		}
+
+		f, found := c.structInfoOf(t).byName[name]
+		if !found || f.PkgPath != "" {
+			return nil, nil
+		}
+		if head == nil {
+			head = f
+		}
+
+		index = append(index, f.Index...)
+		names = append(names, f.Name)
+		field = f
+		t = f.Type
+	}
+
+	nested := *field
+	nested.Index = index
+	nested.Name = strings.Join(names, ".")
+	return &nested, head
+}
+
+// addFieldPlan adds copying fromField to toField, unless destination is unexported or `readonly`, or source is `writeonly`
+func (c *copier) addFieldPlan(plan *structPlan, fromField, toField *fieldInfo) {
+	if toField.PkgPath != "" || toField.tag.ReadOnly || fromField.tag.WriteOnly {
+		return
+	}
+	plan.fields = append(plan.fields, c.compileFieldPlan(fromField, toField))
+}
+
+func (c *copier) compileFieldPlan(fromField, toField *fieldInfo) fieldPlan {
+	fromType, toType := fromField.Type, toField.Type
+	stringify := fromField.tag.String || toField.tag.String
+	direct := !stringify && isBasicKind(fromType.Kind()) && isBasicKind(toType.Kind()) &&
+		fromType.ConvertibleTo(toType) &&
+		c.transformerRepository.Get(Target{From: fromType, To: toType}) == nil
+
+	return fieldPlan{
+		from:      fromField,
+		to:        toField,
+		name:      fromField.Name,
+		policy:    overwritePolicyOf(OverwriteAlways, fromField.StructField, toField.StructField),
+		direct:    direct,
+		stringify: stringify,
+	}
+}
+
+// fieldByIndex returns nested field, or invalid value if embedded pointer is nil
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	f, err := v.FieldByIndexErr(index)
+	if err != nil {
+		return reflect.Value{}
+	}
+	return f
+}
+
+// fieldByIndexAlloc returns nested field, allocating nil embedded pointers
+func fieldByIndexAlloc(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+func isBasicKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,176 @@
+package structmapper
+
+import (
+	"reflect"
+
+	"github.com/pkg/errors"
+)
+
+// Profile is field-level configuration of mapping struct From to struct To, consulted before tags.
+// Register it by Mapper.RegisterProfile.
+type Profile[From, To any] struct {
+	profile *profile
+}
+
+// MappingProfile is Profile of any type pair
+type MappingProfile interface {
+	// Target of types mapped by the profile
+	Target() Target
+
+	mappingProfile() *profile
+}
+
+type profile struct {
+	target Target
+	// in order of configuration
+	fields  []fieldConfig
+	ignored []string
+}
+
+// clone returns copy of p, independent of later configuration of p
+func (p *profile) clone() *profile {
+	return &profile{
+		target:  p.target,
+		fields:  append([]fieldConfig(nil), p.fields...),
+		ignored: append([]string(nil), p.ignored...),
+	}
+}
+
+type fieldConfig struct {
+	// destination field name
+	name   string
+	source FieldSource
+}
+
+// FieldSource is value of destination field configured by Profile.ForField
+type FieldSource interface {
+	fieldSource()
+}
+
+// source field by name
+type fromFieldSource struct {
+	name string
+}
+
+func (*fromFieldSource) fieldSource() {}
+
+// value computed from source struct
+type computeSource struct {
+	fromType reflect.Type
+	compute  func(from reflect.Value) reflect.Value
+}
+
+func (*computeSource) fieldSource() {}
+
+// CreateMap returns empty Profile of From -> To
+func CreateMap[From, To any]() *Profile[From, To] {
+	return &Profile[From, To]{
+		profile: &profile{
+			target: Target{From: typeOf[From](), To: typeOf[To]()},
+		},
+	}
+}
+
+// ForField sets source of destination field by field name
+func (p *Profile[From, To]) ForField(name string, source FieldSource) *Profile[From, To] {
+	if compute, ok := source.(*computeSource); ok && compute.fromType != p.profile.target.From {
+		panic(errors.Errorf("Compute of %+v can't be used for %+v", compute.fromType, p.profile.target))
+	}
+
+	p.profile.fields = append(p.profile.fields, fieldConfig{name: name, source: source})
+	return p
+}
+
+// Ignore leaves destination fields by name untouched, and doesn't match source fields by name.
+// Ignored fields are out of strict mode.
+func (p *Profile[From, To]) Ignore(names ...string) *Profile[From, To] {
+	p.profile.ignored = append(p.profile.ignored, names...)
+	return p
+}
+
+// Target of MappingProfile
+func (p *Profile[From, To]) Target() Target {
+	return p.profile.target
+}
+
+func (p *Profile[From, To]) mappingProfile() *profile {
+	return p.profile
+}
+
+// FromField copies source field by name, which is not matched by names any more
+func FromField(name string) FieldSource {
+	return &fromFieldSource{name: name}
+}
+
+// Compute sets value returned by fn of source struct, converted to destination field as matched field is
+func Compute[From, V any](fn func(From) V) FieldSource {
+	return &computeSource{
+		fromType: typeOf[From](),
+		compute: func(from reflect.Value) reflect.Value {
+			v := fn(from.Interface().(From))
+			return reflect.ValueOf(&v).Elem()
+		},
+	}
+}
+
+func (m *mapper) RegisterProfile(profile MappingProfile) Mapper {
+	m.mustNotFrozen()
+	target := profile.Target()
+	if target.From.Kind() != reflect.Struct || target.To.Kind() != reflect.Struct {
+		panic(errors.Errorf("%+v is not struct types", target))
+	}
+
+	// copied, so that configuring the profile later doesn't change registered or frozen one
+	m.profiles.Store(target, profile.mappingProfile().clone())
+	m.configure(nil)
+	return m
+}
+
+// applyProfile adds fields configured by profile to plan, and returns destination and source fields configured
+func (c *copier) applyProfile(plan *structPlan, p *profile, fromInfo, toInfo *structInfo) (copied, configured map[*fieldInfo]struct{}, err error) {
+	copied = make(map[*fieldInfo]struct{})
+	configured = make(map[*fieldInfo]struct{})
+
+	for _, config := range p.fields {
+		toField, found := toInfo.byName[config.name]
+		if !found {
+			return nil, nil, errors.Errorf("%+v: no destination field %s", p.target, config.name)
+		}
+
+		switch source := config.source.(type) {
+		case *fromFieldSource:
+			fromField, found := fromInfo.byName[source.name]
+			if !found {
+				return nil, nil, errors.Errorf("%+v: no source field %s", p.target, source.name)
+			}
+			c.addFieldPlan(plan, fromField, toField)
+			configured[fromField] = struct{}{}
+
+		case *computeSource:
+			plan.fields = append(plan.fields, fieldPlan{
+				to:      toField,
+				name:    toField.Name,
+				compute: source.compute,
+				policy:  overwritePolicyOf(OverwriteAlways, toField.StructField),
+			})
+		}
+		copied[toField] = struct{}{}
+	}
+
+	for _, name := range p.ignored {
+		toField, toFound := toInfo.byName[name]
+		fromField, fromFound := fromInfo.byName[name]
+		if !toFound && !fromFound {
+			return nil, nil, errors.Errorf("%+v: no field %s to ignore", p.target, name)
+		}
+
+		if toFound {
+			copied[toField] = struct{}{}
+		}
+		if fromFound {
+			configured[fromField] = struct{}{}
+		}
+	}
+
+	return copied, configured, nil
+}
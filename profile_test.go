@@ -0,0 +1,166 @@
+package structmapper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// without tags, as generated types
+type PersonDTO struct {
+	ID       string
+	First    string
+	Last     string
+	Internal string
+}
+
+type PersonMessage struct {
+	Id       string
+	FullName string
+	Internal string
+	Age      int
+}
+
+func personProfile() *Profile[PersonDTO, PersonMessage] {
+	return CreateMap[PersonDTO, PersonMessage]().
+		ForField("Id", FromField("ID")).
+		ForField("FullName", Compute(func(p PersonDTO) string {
+			return p.First + " " + p.Last
+		})).
+		Ignore("Internal", "Age")
+}
+
+func TestProfile(t *testing.T) {
+	from := &PersonDTO{ID: "12345", First: "Taro", Last: "Yamada", Internal: "secret"}
+
+	t.Run("configured fields", func(t *testing.T) {
+		to := &PersonMessage{Internal: "kept", Age: 32}
+
+		err := New().
+			RegisterProfile(personProfile()).
+			From(from).
+			Merge().
+			CopyTo(to)
+		if assert.NoError(t, err) {
+			assert.Equal(t, &PersonMessage{Id: "12345", FullName: "Taro Yamada", Internal: "kept", Age: 32}, to)
+		}
+	})
+
+	t.Run("strict mode", func(t *testing.T) {
+		to := new(PersonMessage)
+
+		// source fields of Compute are ignored explicitly
+		err := New().
+			RegisterProfile(personProfile().Ignore("First", "Last")).
+			Strict(StrictBoth).
+			From(from).
+			CopyTo(to)
+		if assert.NoError(t, err) {
+			assert.Equal(t, &PersonMessage{Id: "12345", FullName: "Taro Yamada"}, to)
+		}
+
+		err = New().
+			RegisterProfile(CreateMap[PersonDTO, PersonMessage]().ForField("Id", FromField("ID"))).
+			Strict(StrictBoth).
+			From(from).
+			CopyTo(to)
+		assert.ErrorIs(t, err, ErrUnmappedField)
+	})
+
+	t.Run("nested struct", func(t *testing.T) {
+		to := new(struct {
+			People []PersonMessage
+		})
+
+		err := New().
+			RegisterProfile(personProfile()).
+			From(&struct{ People []PersonDTO }{People: []PersonDTO{*from}}).
+			CopyTo(to)
+		if assert.NoError(t, err) {
+			assert.Equal(t, []PersonMessage{{Id: "12345", FullName: "Taro Yamada"}}, to.People)
+		}
+	})
+
+	t.Run("computed value is converted", func(t *testing.T) {
+		to := new(PersonMessage)
+
+		err := New().
+			RegisterProfile(CreateMap[PersonDTO, PersonMessage]().
+				ForField("Age", Compute(func(p PersonDTO) int64 {
+					return int64(len(p.First))
+				}))).
+			From(from).
+			CopyTo(to)
+		if assert.NoError(t, err) {
+			assert.Equal(t, 4, to.Age)
+		}
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		err := New().
+			RegisterProfile(CreateMap[PersonDTO, PersonMessage]().ForField("Name", FromField("ID"))).
+			From(from).
+			CopyTo(new(PersonMessage))
+		assert.EqualError(t, err, "PersonDTO: structmapper.PersonDTO -> structmapper.PersonMessage: no destination field Name")
+
+		err = New().
+			RegisterProfile(CreateMap[PersonDTO, PersonMessage]().ForField("Id", FromField("Identifier"))).
+			From(from).
+			CopyTo(new(PersonMessage))
+		assert.EqualError(t, err, "PersonDTO: structmapper.PersonDTO -> structmapper.PersonMessage: no source field Identifier")
+	})
+
+	t.Run("compute of other type", func(t *testing.T) {
+		assert.Panics(t, func() {
+			CreateMap[PersonDTO, PersonMessage]().ForField("FullName", Compute(func(p PersonMessage) string {
+				return p.FullName
+			}))
+		})
+	})
+
+	t.Run("not struct types", func(t *testing.T) {
+		assert.Panics(t, func() {
+			New().RegisterProfile(CreateMap[*PersonDTO, PersonMessage]())
+		})
+	})
+
+	t.Run("frozen mapper keeps profiles", func(t *testing.T) {
+		to := new(PersonMessage)
+
+		err := New().
+			RegisterProfile(personProfile()).
+			Freeze().
+			From(from).
+			CopyTo(to)
+		if assert.NoError(t, err) {
+			assert.Equal(t, "Taro Yamada", to.FullName)
+		}
+	})
+
+	t.Run("configured after registration", func(t *testing.T) {
+		profile := personProfile()
+		frozen := New().RegisterProfile(profile).Freeze()
+		profile.Ignore("FullName").ForField("Internal", FromField("Internal"))
+
+		to := new(PersonMessage)
+		if assert.NoError(t, frozen.From(from).CopyTo(to)) {
+			assert.Equal(t, &PersonMessage{Id: "12345", FullName: "Taro Yamada"}, to)
+		}
+	})
+
+	t.Run("convertible types", func(t *testing.T) {
+		type F struct{ A, B string }
+		type T struct{ A, B string }
+
+		to := new(T)
+		if assert.NoError(t, New().RegisterProfile(CreateMap[F, T]().Ignore("A")).From(&F{A: "a", B: "b"}).CopyTo(to)) {
+			assert.Equal(t, &T{B: "b"}, to)
+		}
+
+		// same type
+		same := new(F)
+		if assert.NoError(t, New().RegisterProfile(CreateMap[F, F]().Ignore("A")).From(&F{A: "a", B: "b"}).CopyTo(same)) {
+			assert.Equal(t, &F{B: "b"}, same)
+		}
+	})
+}
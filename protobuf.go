@@ -16,88 +16,68 @@ func ProtobufModule(m Mapper) {
 	registerWrappers(m)
 }
 
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	timestampType = reflect.TypeOf(timestamp.Timestamp{})
+)
+
 // for ptypes/timestamp.Timestamp
 func registerTimestamp(m Mapper) {
 	// string <-> Timestamp
-	m.RegisterTransformer(
-		Target{
-			From: reflect.TypeOf(""),
-			To:   reflect.TypeOf(timestamp.Timestamp{}),
-		},
+	m.RegisterBidirectional(stringType, timestampType,
 		func(from reflect.Value, _ reflect.Type) (reflect.Value, error) {
 			t, err := time.Parse(time.RFC3339, from.String())
 			if err != nil {
 				return reflect.ValueOf(nil), errors.WithStack(err)
 			}
-
-			ts, err := ptypes.TimestampProto(t)
-			if err != nil {
-				return reflect.ValueOf(nil), errors.WithStack(err)
-			}
-
-			return reflect.ValueOf(*ts), nil
-		},
-	)
-	m.RegisterTransformer(
-		Target{
-			From: reflect.TypeOf(timestamp.Timestamp{}),
-			To:   reflect.TypeOf(""),
+			return timestampOf(t)
 		},
 		func(from reflect.Value, _ reflect.Type) (reflect.Value, error) {
-			ts, ok := from.Interface().(timestamp.Timestamp)
-			if !ok {
-				return reflect.ValueOf(nil), errors.Errorf("Invalid value was found, expected timestamp.Timestamp, but was %+v", from)
-			}
-
-			t, err := ptypes.Timestamp(&ts)
+			t, err := timeOf(from)
 			if err != nil {
-				return reflect.ValueOf(""), errors.WithStack(err)
+				return reflect.ValueOf(""), err
 			}
-
 			return reflect.ValueOf(t.Format(time.RFC3339)), nil
 		},
 	)
 
 	// time.Time <-> Timestamp
-	m.RegisterTransformer(
-		Target{
-			From: reflect.TypeOf(time.Time{}),
-			To:   reflect.TypeOf(timestamp.Timestamp{}),
-		},
+	m.RegisterBidirectional(timeType, timestampType,
 		func(from reflect.Value, _ reflect.Type) (reflect.Value, error) {
 			t, ok := from.Interface().(time.Time)
 			if !ok {
 				return reflect.ValueOf(nil), errors.Errorf("Invalid value was found, expected time.Time, but was %+v", from)
 			}
-
-			ts, err := ptypes.TimestampProto(t)
-			if err != nil {
-				return reflect.ValueOf(nil), errors.WithStack(err)
-			}
-
-			return reflect.ValueOf(*ts), nil
-		},
-	)
-	m.RegisterTransformer(
-		Target{
-			From: reflect.TypeOf(timestamp.Timestamp{}),
-			To:   reflect.TypeOf(time.Time{}),
+			return timestampOf(t)
 		},
 		func(from reflect.Value, _ reflect.Type) (reflect.Value, error) {
-			ts, ok := from.Interface().(timestamp.Timestamp)
-			if !ok {
-				return reflect.ValueOf(nil), errors.Errorf("Invalid value was found, expected timestamp.Timestamp, but was %+v", from)
-			}
-
-			t, err := ptypes.Timestamp(&ts)
+			t, err := timeOf(from)
 			if err != nil {
-				return reflect.ValueOf(""), errors.WithStack(err)
+				return reflect.ValueOf(time.Time{}), err
 			}
-
 			return reflect.ValueOf(t), nil
 		},
 	)
+}
+
+// timestampOf returns Timestamp value of t
+func timestampOf(t time.Time) (reflect.Value, error) {
+	ts, err := ptypes.TimestampProto(t)
+	if err != nil {
+		return reflect.ValueOf(nil), errors.WithStack(err)
+	}
+	return reflect.ValueOf(*ts), nil
+}
+
+// timeOf returns time.Time of Timestamp value
+func timeOf(from reflect.Value) (time.Time, error) {
+	ts, ok := forceAddr(from).Interface().(*timestamp.Timestamp)
+	if !ok {
+		return time.Time{}, errors.Errorf("Invalid value was found, expected timestamp.Timestamp, but was %+v", from)
+	}
 
+	t, err := ptypes.Timestamp(ts)
+	return t, errors.WithStack(err)
 }
 
 // for ptypes/wrappers.*
@@ -0,0 +1,95 @@
+package structmapper
+
+import (
+	"reflect"
+
+	"github.com/pkg/errors"
+)
+
+// ErrCycle is cause of MappingError on source struct referencing itself, unless PreserveReferences is set
+var ErrCycle = errors.New("cycle of references")
+
+// reference of source pointer converted to destination type
+type reference struct {
+	ptr  uintptr
+	from reflect.Type
+	to   reflect.Type
+}
+
+func (c *copyCommand) PreserveReferences() CopyCommand {
+	c.preserveReferences = true
+	return c
+}
+
+// convertReference converts non-nil pointer from to new pointer of toType,
+// or returns the pointer converted from the same source pointer before
+func (c *copier) convertReference(from reflect.Value, toType reflect.Type) (reflect.Value, error) {
+	key := reference{ptr: from.Pointer(), from: from.Type(), to: toType}
+	if to, ok := c.references[key]; ok {
+		return to, nil
+	}
+
+	// register before converting, for cycles back to this pointer
+	to := reflect.New(toType.Elem())
+	c.addReference(from, to)
+
+	v, err := c.convert(from.Elem(), toType.Elem())
+	if err != nil {
+		return to, err
+	}
+	to.Elem().Set(v)
+	return to, nil
+}
+
+// mergedReference sets to the pointer merged from source pointer from before, and reports whether it was.
+// Otherwise, it registers existing or new pointer of to as destination of from, to be merged into.
+func (c *copier) mergedReference(to, from reflect.Value) bool {
+	if merged, ok := c.references[reference{ptr: from.Pointer(), from: from.Type(), to: to.Type()}]; ok {
+		to.Set(merged)
+		return true
+	}
+
+	if to.IsNil() {
+		to.Set(reflect.New(to.Type().Elem()))
+	}
+	// register before merging, for cycles back to this pointer
+	c.addReference(from, to.Elem().Addr())
+	return false
+}
+
+// addReference registers to as destination of source pointer from
+func (c *copier) addReference(from, to reflect.Value) {
+	if c.references == nil {
+		c.references = make(map[reference]reflect.Value)
+	}
+	c.references[reference{ptr: from.Pointer(), from: from.Type(), to: to.Type()}] = to
+}
+
+// canReference reports whether from is converted to pointer of toType by convertReference
+func (c *copier) canReference(from reflect.Value, toType reflect.Type) bool {
+	return c.preserveReferences && from.Kind() == reflect.Ptr && !from.IsNil() &&
+		toType.Kind() == reflect.Ptr && toType.Elem().Kind() != reflect.Ptr
+}
+
+// visit pushes addressable source struct being copied to toType, failing with ErrCycle if it's already being copied.
+// It reports whether from is pushed, to be popped by leaveVisit.
+func (c *copier) visit(from reflect.Value, toType reflect.Type) (bool, error) {
+	if !from.CanAddr() {
+		return false, nil
+	}
+
+	key := reference{ptr: from.Addr().Pointer(), from: from.Type(), to: toType}
+	for _, visiting := range c.visiting {
+		if visiting == key {
+			return false, errors.WithMessagef(ErrCycle, "%+v references itself", from.Type())
+		}
+	}
+
+	c.visiting = append(c.visiting, key)
+	return true, nil
+}
+
+// leaveVisit pops source struct pushed by visit
+func (c *copier) leaveVisit() {
+	c.visiting = c.visiting[:len(c.visiting)-1]
+}
@@ -0,0 +1,105 @@
+package structmapper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type TreeNode struct {
+	Name     string
+	Parent   *TreeNode
+	Children []*TreeNode
+}
+
+type TreeNodeDTO struct {
+	Name     string
+	Parent   *TreeNodeDTO
+	Children []*TreeNodeDTO
+}
+
+type SharedOwners struct {
+	Owner  *TreeNode
+	Admin  *TreeNode
+	Admins map[string]*TreeNode
+}
+
+type SharedOwnersDTO struct {
+	Owner  *TreeNodeDTO
+	Admin  *TreeNodeDTO
+	Admins map[string]*TreeNodeDTO
+}
+
+func newTree() *TreeNode {
+	root := &TreeNode{Name: "root"}
+	root.Children = []*TreeNode{{Name: "a", Parent: root}, {Name: "b", Parent: root}}
+	return root
+}
+
+func TestReferences(t *testing.T) {
+	t.Run("cycle error by default", func(t *testing.T) {
+		err := New().From(newTree()).CopyTo(new(TreeNodeDTO))
+		assert.ErrorIs(t, err, ErrCycle)
+		assert.EqualError(t, err, "TreeNode.Children[0].Parent: structmapper.TreeNode references itself: cycle of references")
+	})
+
+	t.Run("preserve cycles", func(t *testing.T) {
+		to := new(TreeNodeDTO)
+		if assert.NoError(t, New().From(newTree()).PreserveReferences().CopyTo(to)) && assert.Len(t, to.Children, 2) {
+			assert.Equal(t, "a", to.Children[0].Name)
+			assert.Same(t, to, to.Children[0].Parent)
+			assert.Same(t, to, to.Children[1].Parent)
+		}
+	})
+
+	t.Run("nil destination", func(t *testing.T) {
+		assert.NoError(t, New().From(nil).CopyTo(nil))
+		assert.NoError(t, New().From(nil).PreserveReferences().CopyTo(nil))
+		assert.EqualError(t, New().From(newTree()).PreserveReferences().CopyTo(nil), "can't copy *structmapper.TreeNode to nil")
+	})
+
+	t.Run("preserve cycles on merge", func(t *testing.T) {
+		to := &TreeNodeDTO{Name: "old"}
+		if assert.NoError(t, New().From(newTree()).Merge().PreserveReferences().CopyTo(to)) && assert.Len(t, to.Children, 2) {
+			assert.Equal(t, "root", to.Name)
+			assert.Same(t, to, to.Children[0].Parent)
+			assert.Same(t, to, to.Children[1].Parent)
+		}
+	})
+
+	t.Run("preserve shared pointers on merge", func(t *testing.T) {
+		node := &TreeNode{Name: "taro"}
+		owner := &TreeNodeDTO{Name: "old"}
+		to := &SharedOwnersDTO{Owner: owner}
+		if assert.NoError(t, New().From(&SharedOwners{Owner: node, Admin: node}).Merge().PreserveReferences().CopyTo(to)) {
+			assert.Same(t, owner, to.Owner)
+			assert.Same(t, owner, to.Admin)
+			assert.Equal(t, "taro", owner.Name)
+		}
+	})
+
+	t.Run("preserve shared pointers", func(t *testing.T) {
+		node := &TreeNode{Name: "taro"}
+		from := &SharedOwners{Owner: node, Admin: node, Admins: map[string]*TreeNode{"taro": node}}
+
+		to := new(SharedOwnersDTO)
+		if assert.NoError(t, New().From(from).PreserveReferences().CopyTo(to)) {
+			assert.Equal(t, "taro", to.Owner.Name)
+			assert.Same(t, to.Owner, to.Admin)
+			assert.Same(t, to.Owner, to.Admins["taro"])
+		}
+
+		// copied separately by default
+		to = new(SharedOwnersDTO)
+		if assert.NoError(t, New().From(from).CopyTo(to)) {
+			assert.Equal(t, to.Owner, to.Admin)
+			assert.NotSame(t, to.Owner, to.Admin)
+		}
+	})
+
+	t.Run("cycle to map", func(t *testing.T) {
+		to := map[string]interface{}{}
+		err := New().From(newTree()).CopyTo(&to)
+		assert.ErrorIs(t, err, ErrCycle)
+	})
+}
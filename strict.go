@@ -0,0 +1,56 @@
+package structmapper
+
+import (
+	"reflect"
+
+	"github.com/pkg/errors"
+)
+
+// Mode of failing on unmapped fields
+type StrictMode int
+
+const (
+	// Ignore unmapped fields (default)
+	StrictNone StrictMode = 0
+	// Fail on source field without destination field
+	StrictSource StrictMode = 1 << 0
+	// Fail on destination field which received no value
+	StrictDestination StrictMode = 1 << 1
+	// Fail on both of unmapped source and destination fields
+	StrictBoth = StrictSource | StrictDestination
+)
+
+// ErrUnmappedField is cause of MappingError in strict mode
+var ErrUnmappedField = errors.New("unmapped field")
+
+// isIgnored reports whether field of names is out of strict mode: unexported, `structmapper:",ignore"` tagged, or excluded by `-`
+func isIgnored(field reflect.StructField, names []string) bool {
+	return field.PkgPath != "" || tagOf(field).Ignore || len(names) == 0
+}
+
+func (c *copier) unmappedSourceError(fromField reflect.StructField, toType reflect.Type) error {
+	return &MappingError{
+		Path: c.pathString(fieldSegment(fromField.Name)),
+		From: fromField.Type,
+		To:   toType,
+		Err:  errors.WithMessagef(ErrUnmappedField, "no destination field in %+v", toType),
+	}
+}
+
+func (c *copier) unmappedKeyError(key reflect.Value, toType reflect.Type) error {
+	return &MappingError{
+		Path: c.pathString(fieldSegment(key.String())),
+		From: key.Type(),
+		To:   toType,
+		Err:  errors.WithMessagef(ErrUnmappedField, "no destination field in %+v", toType),
+	}
+}
+
+func (c *copier) unmappedDestinationError(fromType reflect.Type, toField reflect.StructField) error {
+	return &MappingError{
+		Path: c.pathString(fieldSegment(toField.Name)),
+		From: fromType,
+		To:   toField.Type,
+		Err:  errors.WithMessagef(ErrUnmappedField, "destination field %s received no value", toField.Name),
+	}
+}
@@ -0,0 +1,102 @@
+package structmapper
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/structmapper/structmapper/test/dto"
+	"github.com/structmapper/structmapper/test/proto"
+)
+
+type StrictFrom struct {
+	Name    string `json:"name"`
+	Renamed string `json:"renamed"`
+	Skipped string `json:"skipped" structmapper:",ignore"`
+}
+
+type StrictTo struct {
+	Name     string `json:"name"`
+	Missing  string `json:"missing"`
+	Computed string `json:"computed" structmapper:",ignore"`
+}
+
+func TestStrict(t *testing.T) {
+	cases := []struct {
+		Name  string
+		Mode  StrictMode
+		From  interface{}
+		Paths []string
+	}{
+		{
+			Name: "none",
+			Mode: StrictNone,
+			From: &StrictFrom{},
+		},
+		{
+			Name:  "source",
+			Mode:  StrictSource,
+			From:  &StrictFrom{},
+			Paths: []string{"StrictFrom.Renamed"},
+		},
+		{
+			Name:  "destination",
+			Mode:  StrictDestination,
+			From:  &StrictFrom{},
+			Paths: []string{"StrictFrom.Missing"},
+		},
+		{
+			Name:  "both",
+			Mode:  StrictBoth,
+			From:  &StrictFrom{},
+			Paths: []string{"StrictFrom.Renamed", "StrictFrom.Missing"},
+		},
+		{
+			Name:  "map source",
+			Mode:  StrictBoth,
+			From:  map[string]interface{}{"name": "name", "renamed": "renamed"},
+			Paths: []string{"Missing", "renamed"},
+		},
+	}
+
+	for _, _c := range cases {
+		c := _c
+		t.Run(c.Name, func(t *testing.T) {
+			err := New().From(c.From).Strict(c.Mode).CollectErrors().CopyTo(new(StrictTo))
+			if len(c.Paths) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			var mappingErrs MappingErrors
+			if assert.True(t, errors.As(err, &mappingErrs)) {
+				paths := make([]string, 0, len(mappingErrs))
+				for _, e := range mappingErrs {
+					paths = append(paths, e.Path)
+				}
+				assert.ElementsMatch(t, c.Paths, paths)
+			}
+			assert.True(t, errors.Is(err, ErrUnmappedField))
+		})
+	}
+}
+
+func TestStrictMapper(t *testing.T) {
+	mapper := New().Strict(StrictBoth)
+
+	err := mapper.From(&StrictFrom{}).CopyTo(new(StrictTo))
+	assert.EqualError(t, err, "StrictFrom.Renamed: no destination field in structmapper.StrictTo: unmapped field")
+
+	// overridden by command
+	assert.NoError(t, mapper.From(&StrictFrom{}).Strict(StrictNone).CopyTo(new(StrictTo)))
+}
+
+func TestStrictFixtures(t *testing.T) {
+	mapper := New().
+		Strict(StrictBoth).
+		Install(ProtobufModule).
+		Install(StringerModule)
+
+	assert.NoError(t, mapper.From(&dto.User{Sex: dto.SexMale}).CopyTo(new(proto.User)))
+	assert.NoError(t, mapper.From(&proto.User{Sex: "Male"}).CopyTo(new(dto.User)))
+}
@@ -4,26 +4,27 @@ package structmapper
 // extend mapping by struct tag
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"reflect"
-	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/pkg/errors"
 )
 
 // New Mapper
 func New() Mapper {
-	return &mapper{
-		transformerRepository: newTransformerRepository(),
-		logger:                newNopLogger(),
-	}
+	m := &mapper{transformerRepository: newTransformerRepository()}
+	m.config.Store(&mapperConfig{tagNames: defaultTagNames, logger: newNopLogger()})
+	return m
 }
 
 // Mapper Struct mapper
 type Mapper interface {
 	// Copy struct to other struct. Field mapping by `structmapper` tag, `json` tag, or field name.
+	// map[string]interface{} can be used as source or destination instead of struct.
 	From(fromValue interface{}) CopyCommand
 
 	// Register Transformer matches by TargerMatcher
@@ -32,10 +33,52 @@ type Mapper interface {
 	// Register Transformer matches by TargerMatcher
 	RegisterTransformerFunc(matcher TypeMatcherFunc, transformer Transformer) Mapper
 
+	// Register ContextTransformer matches by TargerMatcher
+	RegisterContextTransformer(matcher TypeMatcher, transformer ContextTransformer) Mapper
+
+	// Register Transformers of both directions, forward of a -> b and backward of b -> a
+	RegisterBidirectional(a, b reflect.Type, forward, backward Transformer) Mapper
+
+	// Register ContextTransformer tried before ones of lower priority, and return its handle.
+	// Other Register methods register by priority 0. Exact Target is tried before other TypeMatchers of the same priority,
+	// and registering the same exact Target by the same priority again replaces its Transformer, returning the same handle.
+	AddTransformer(matcher TypeMatcher, priority int, transformer ContextTransformer) TransformerHandle
+
+	// Deregister Transformer of handle, panics with ErrUnknownTransformer if it's not registered
+	RemoveTransformer(handle TransformerHandle) Mapper
+
+	// Replace Transformer of handle keeping its matcher and priority, panics with ErrUnknownTransformer if it's not registered
+	ReplaceTransformer(handle TransformerHandle, transformer ContextTransformer) Mapper
+
+	// Register ConcreteTypeResolver for interface typed destination
+	RegisterResolver(interfaceType reflect.Type, resolver ConcreteTypeResolver) Mapper
+
+	// Register copy of MappingProfile created by CreateMap, consulted before tags
+	RegisterProfile(profile MappingProfile) Mapper
+
+	// Register MapHooks called before and after fields of struct Target are copied, either can be nil.
+	// They are called after BeforeMapper and AfterMapper of destination, in order of registration.
+	RegisterHooks(target Target, before, after MapHook) Mapper
+
+	// Set struct tag keys to match names by in order, `structmapper` and `json` by default
+	TagNames(tagNames ...string) Mapper
+
+	// Set NameMatchers tried in order to match struct fields when no field has exactly the same name
+	MatchNames(matchers ...NameMatcher) Mapper
+
+	// Set StrictMode of all copies, fail on unmapped fields except `structmapper:",ignore"` tagged
+	Strict(mode StrictMode) Mapper
+
+	// Set Limits of all copies, failing with LimitError on exceeding input
+	Limit(limits Limits) Mapper
+
 	// Install Module
 	Install(Module) Mapper
 
 	EnableLogging() Mapper
+
+	// Return immutable copy of this Mapper, which panics with ErrFrozen on registration or configuration
+	Freeze() Mapper
 }
 
 // Mapper installable module
@@ -43,8 +86,28 @@ type Module func(Mapper)
 
 // Copy to ...
 type CopyCommand interface {
+	// Update existing destination in place instead of replacing it.
+	// Nested structs, maps from struct and pointer targets are merged recursively, and fields missing in source are kept.
+	Merge() CopyCommand
+
+	// Set OverwritePolicy of source fields. `structmapper:",omitempty"` or `structmapper:",omitnil"` tag overrides it per field.
+	Overwrite(policy OverwritePolicy) CopyCommand
+
+	// Continue mapping on error, and return MappingErrors of all failing fields
+	CollectErrors() CopyCommand
+
+	// Set StrictMode of this copy instead of Mapper's one
+	Strict(mode StrictMode) CopyCommand
+
+	// Reproduce pointers shared or cycled in source as the same pointers in destination, instead of failing with ErrCycle on cycles
+	// On Merge, existing destination pointers are merged into and shared as well.
+	PreserveReferences() CopyCommand
+
 	// Copy struct to other struct. Field mapping by `structmapper` tag, `json` tag, or field name.
 	CopyTo(toValue interface{}) error
+
+	// CopyTo with ctx passed to ContextTransformers, failing with ctx.Err() once ctx is done
+	CopyToContext(ctx context.Context, toValue interface{}) error
 }
 
 // Matcher of Transformer target
@@ -79,42 +142,165 @@ func (t Target) String() string {
 	return fmt.Sprintf("%+v -> %+v", t.From, t.To)
 }
 
-// Value transformer
+// Value transformer.
+// Transformer of pointer To is called for pointer destination with dereferenced source, and may return nil to leave it nil.
 type Transformer func(from reflect.Value, toType reflect.Type) (reflect.Value, error)
 
+// Value transformer with context of CopyToContext
+type ContextTransformer func(ctx context.Context, from reflect.Value, toType reflect.Type) (reflect.Value, error)
+
 type copyCommand struct {
 	*mapper
+	copyOptions
+	config    *mapperConfig
 	fromValue interface{}
 }
 
+func (c *copyCommand) Merge() CopyCommand {
+	c.merge = true
+	return c
+}
+
+func (c *copyCommand) Overwrite(policy OverwritePolicy) CopyCommand {
+	c.overwrite = policy
+	return c
+}
+
+func (c *copyCommand) CollectErrors() CopyCommand {
+	c.collectErrors = true
+	return c
+}
+
+func (c *copyCommand) Strict(mode StrictMode) CopyCommand {
+	c.strict = mode
+	return c
+}
+
 func (c *copyCommand) CopyTo(toValue interface{}) (err error) {
-	return c.mapper.Copy(toValue, c.fromValue)
+	return c.CopyToContext(context.Background(), toValue)
+}
+
+func (c *copyCommand) CopyToContext(ctx context.Context, toValue interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	copier := c.mapper.newCopier(c.config, c.copyOptions)
+	copier.ctx = ctx
+	return copier.Copy(toValue, c.fromValue)
+}
+
+// Options of CopyCommand, defaults are set by Mapper
+type copyOptions struct {
+	merge         bool
+	overwrite     OverwritePolicy
+	collectErrors bool
+	strict        StrictMode
+	// destination pointers by source pointers
+	preserveReferences bool
+	limits             Limits
+}
+
+// State of a copy
+type copier struct {
+	*mapper
+	*mapperConfig
+	copyOptions
+	ctx  context.Context
+	path []string
+	errs MappingErrors
+	// destination pointers converted from source pointers, by PreserveReferences
+	references map[reference]reflect.Value
+	// stack of source structs being copied
+	visiting []reference
+	// elements of slices, arrays and maps counted for Limits
+	elements int
 }
 
 type mapper struct {
 	transformerRepository *transformerRepository
-	logger                Logger
+	resolvers             sync.Map // reflect.Type -> ConcreteTypeResolver
+	profiles              sync.Map // Target -> *profile
+	hooks                 sync.Map // Target -> *mapHooks
+	hooksLock             sync.Mutex
+	config                atomic.Pointer[mapperConfig]
+	configLock            sync.Mutex
+	frozen                bool
 }
 
 func (m *mapper) Install(module Module) Mapper {
+	m.mustNotFrozen()
 	module(m)
 	return m
 }
 
 func (m *mapper) EnableLogging() Mapper {
-	m.logger = newStdLogger()
+	m.mustNotFrozen()
+	m.configure(func(config *mapperConfig) {
+		config.logger = newStdLogger()
+	})
+	return m
+}
+
+func (m *mapper) Strict(mode StrictMode) Mapper {
+	m.mustNotFrozen()
+	m.configure(func(config *mapperConfig) {
+		config.options.strict = mode
+	})
 	return m
 }
 
 func (m *mapper) From(fromValue interface{}) CopyCommand {
-	return &copyCommand{mapper: m, fromValue: fromValue}
+	config := m.config.Load()
+	return &copyCommand{mapper: m, copyOptions: config.options, config: config, fromValue: fromValue}
 }
 
 func (m *mapper) Copy(toValue, fromValue interface{}) error {
-	return m.copyValue(reflect.ValueOf(toValue), reflect.ValueOf(fromValue))
+	config := m.config.Load()
+	return m.newCopier(config, config.options).Copy(toValue, fromValue)
+}
+
+func (m *mapper) newCopier(config *mapperConfig, options copyOptions) *copier {
+	return &copier{mapper: m, mapperConfig: config, copyOptions: options, ctx: context.Background()}
 }
 
-func (m *mapper) copyValue(to, from reflect.Value) error {
+func (c *copier) Copy(toValue, fromValue interface{}) error {
+	from := reflect.ValueOf(fromValue)
+	if from.IsValid() {
+		// root of path
+		c.path = []string{indirectType(from.Type()).Name()}
+	}
+	to := reflect.ValueOf(toValue)
+	if !to.IsValid() {
+		if from.IsValid() {
+			return errors.Errorf("can't copy %+v to nil", from.Type())
+		}
+		return nil
+	}
+	if c.canReference(from, to.Type()) {
+		// cycles back to root
+		c.addReference(from, to)
+	}
+
+	if err := c.copyValue(to, from); err != nil {
+		return err
+	}
+	if err := c.ctx.Err(); err != nil {
+		// canceled by the last Transformer
+		return err
+	}
+	if len(c.errs) > 0 {
+		return c.errs
+	}
+	return nil
+}
+
+func (c *copier) copyValue(to, from reflect.Value) error {
+	// Unwrap dynamic value
+	if from.Kind() == reflect.Interface {
+		from = from.Elem()
+	}
+
 	// Return if invalid
 	if !from.IsValid() {
 		return nil
@@ -126,7 +312,40 @@ func (m *mapper) copyValue(to, from reflect.Value) error {
 		return nil
 	}
 
-	v, err := m.convert(indirect(from), indirectType(to.Type()))
+	if to.CanSet() && c.canReference(from, to.Type()) {
+		if c.merge {
+			if c.mergedReference(to, from) {
+				return nil
+			}
+		} else {
+			v, err := c.convertReference(from, to.Type())
+			if err != nil {
+				return err
+			}
+			to.Set(v)
+			return nil
+		}
+	}
+
+	toType := indirectType(to.Type())
+	if c.merge && c.canMerge(indirect(from), toType) {
+		return c.mergeValue(indirectAsNonNil(to), indirect(from))
+	}
+
+	if v, ok, err := c.transformPointer(from, to.Type()); ok {
+		if err != nil {
+			return err
+		}
+		to.Set(v)
+		return nil
+	}
+
+	if toType.Kind() != reflect.Interface {
+		// keep pointer for interface implemented by pointer receiver
+		from = indirect(from)
+	}
+
+	v, err := c.convert(from, toType)
 	if err != nil {
 		return err
 	}
@@ -136,21 +355,45 @@ func (m *mapper) copyValue(to, from reflect.Value) error {
 	return nil
 }
 
-func (m *mapper) convertSlice(from reflect.Value, toType reflect.Type) (reflect.Value, error) {
+func (c *copier) convertSlice(from reflect.Value, toType reflect.Type) (reflect.Value, error) {
+	if from.Kind() == reflect.Slice && from.IsNil() {
+		return reflect.Zero(toType), nil
+	}
+	if err := c.countElements(from); err != nil {
+		return reflect.Zero(toType), err
+	}
+
 	amount := from.Len()
 	destType := toType.Elem()
 	to := reflect.MakeSlice(toType, 0, amount)
 
 	for i := 0; i < amount; i++ {
+		if err := c.ctx.Err(); err != nil {
+			return to, err
+		}
 		source := from.Index(i)
 
-		m.logger.Printf("convertSlice[%d](%+v -> %+v)", i, source, destType)
-		dest, err := m.convert(source, indirectType(destType))
+		c.logger.Printf("convertSlice[%d](%+v -> %+v)", i, source, destType)
+		c.enter(indexSegment(i))
+		var dest reflect.Value
+		var err error
+		var transformed bool
+		if c.canReference(source, destType) {
+			dest, err = c.convertReference(source, destType)
+		} else if dest, transformed, err = c.transformPointer(source, destType); !transformed {
+			dest, err = c.convert(source, indirectType(destType))
+		}
+		c.leave()
 		if err != nil {
-			return to, err
+			if err := c.fail(err); err != nil {
+				return to, err
+			}
+			dest = reflect.Zero(destType)
 		}
 
-		if destType.Kind() == reflect.Ptr {
+		if transformed {
+			to = reflect.Append(to, dest)
+		} else if destType.Kind() == reflect.Ptr {
 			to = reflect.Append(to, forceAddr(dest))
 		} else {
 			to = reflect.Append(to, dest)
@@ -160,43 +403,268 @@ func (m *mapper) convertSlice(from reflect.Value, toType reflect.Type) (reflect.
 	return to, nil
 }
 
-func (m *mapper) convertStruct(from reflect.Value, toType reflect.Type) (reflect.Value, error) {
+func (c *copier) convertArray(from reflect.Value, toType reflect.Type) (reflect.Value, error) {
 	to := reflect.New(toType).Elem()
-	toFields := asNamesToFieldMap(deepFields(to.Type()))
-
-	// Copy from field to field
-	copied := make(map[string]struct{})
-
-	for _, fromField := range deepFields(from.Type()) {
-		if fromValue := from.FieldByName(fromField.Name); fromValue.IsValid() {
-			for _, name := range namesOf(fromField) {
-				if toField, found := toFields[name]; found {
-					// has field
-					if _, ok := copied[toField.Name]; !ok {
-						if toValue := to.FieldByName(toField.Name); toValue.IsValid() && toValue.CanSet() {
-							m.logger.Printf("copyValue(%s:%+v -> %s:%+v)", fromField.Name, fromValue.Kind(), toField.Name, toValue.Kind())
-							if err := m.copyValue(toValue, fromValue); err != nil {
-								return to, err
-							}
-						}
-						copied[toField.Name] = struct{}{}
-					}
-				}
+	if err := c.countElements(from); err != nil {
+		return to, err
+	}
+
+	amount := from.Len()
+	if amount > toType.Len() {
+		return to, errors.Errorf("can't convert data %+v -> %+v: %d elements exceed array length %d", from, toType, amount, toType.Len())
+	}
+
+	destType := toType.Elem()
+	for i := 0; i < amount; i++ {
+		if err := c.ctx.Err(); err != nil {
+			return to, err
+		}
+		source := from.Index(i)
+
+		c.logger.Printf("convertArray[%d](%+v -> %+v)", i, source, destType)
+		c.enter(indexSegment(i))
+		dest, err := c.convertElem(source, destType)
+		c.leave()
+		if err != nil {
+			if err := c.fail(err); err != nil {
+				return to, err
+			}
+			continue
+		}
+
+		to.Index(i).Set(dest)
+	}
+
+	return to, nil
+}
+
+func (c *copier) convertMap(from reflect.Value, toType reflect.Type) (reflect.Value, error) {
+	if from.IsNil() {
+		return reflect.Zero(toType), nil
+	}
+	if err := c.countElements(from); err != nil {
+		return reflect.Zero(toType), err
+	}
+
+	keyType := toType.Key()
+	elemType := toType.Elem()
+	to := reflect.MakeMapWithSize(toType, from.Len())
+
+	iter := from.MapRange()
+	for iter.Next() {
+		if err := c.ctx.Err(); err != nil {
+			return to, err
+		}
+		c.logger.Printf("convertMap[%+v](%+v -> %+v)", iter.Key(), iter.Value(), elemType)
+		c.enter(keySegment(iter.Key()))
+		key, err := c.convertElem(iter.Key(), keyType)
+		if err != nil {
+			err = c.fail(err)
+			c.leave()
+			if err != nil {
+				return to, err
+			}
+			continue
+		}
+
+		elem, err := c.convertElem(iter.Value(), elemType)
+		c.leave()
+		if err != nil {
+			if err := c.fail(err); err != nil {
+				return to, err
 			}
+			continue
 		}
+
+		to.SetMapIndex(key, elem)
 	}
 
 	return to, nil
 }
 
+// convertElem converts an array or map element, keeping nil pointers nil
+func (c *copier) convertElem(from reflect.Value, toType reflect.Type) (reflect.Value, error) {
+	if from.Kind() == reflect.Ptr && from.IsNil() && toType.Kind() == reflect.Ptr {
+		return reflect.Zero(toType), nil
+	} else if c.canReference(from, toType) {
+		return c.convertReference(from, toType)
+	} else if v, ok, err := c.transformPointer(from, toType); ok {
+		return v, err
+	}
+
+	v, err := c.convert(from, indirectType(toType))
+	if err != nil {
+		return v, err
+	}
+
+	if toType.Kind() == reflect.Ptr {
+		return forceAddr(v), nil
+	}
+	return v, nil
+}
+
+// transformPointer converts from by Transformer of pointer toType if registered, which may return nil
+func (c *copier) transformPointer(from reflect.Value, toType reflect.Type) (reflect.Value, bool, error) {
+	if toType.Kind() != reflect.Ptr || from.Kind() == reflect.Interface {
+		return reflect.Value{}, false, nil
+	}
+	from = indirect(from)
+	transformer := c.transformerRepository.Get(Target{To: toType, From: from.Type()})
+	if transformer == nil {
+		return reflect.Value{}, false, nil
+	}
+
+	v, err := transformer(c.ctx, from, toType)
+	if err != nil {
+		return reflect.Zero(toType), true, c.wrapError(err, from, toType)
+	}
+	return v, true, nil
+}
+
+// canMerge reports whether from can be merged into existing struct of toType, or existing map of toType from struct
+func (c *copier) canMerge(from reflect.Value, toType reflect.Type) bool {
+	switch {
+	case toType.Kind() == reflect.Struct && hasExportedFields(toType):
+		if from.Kind() != reflect.Struct && !(from.Kind() == reflect.Map && from.Type().Key().Kind() == reflect.String) {
+			return false
+		}
+	case toType.Kind() == reflect.Map && toType.Key().Kind() == reflect.String:
+		if from.Kind() != reflect.Struct || !hasExportedFields(from.Type()) {
+			return false
+		}
+	default:
+		return false
+	}
+
+	return c.transformerRepository.Get(Target{To: toType, From: from.Type()}) == nil && !c.canScan(toType)
+}
+
+func (c *copier) mergeValue(to, from reflect.Value) error {
+	if to.Kind() == reflect.Map {
+		if to.IsNil() {
+			to.Set(reflect.MakeMap(to.Type()))
+		}
+		return c.copyStructToMap(to, from)
+	}
+	if from.Kind() == reflect.Map {
+		return c.copyMapToStruct(to, from)
+	}
+	return c.copyStruct(to, from)
+}
+
+func (c *copier) convertStruct(from reflect.Value, toType reflect.Type) (reflect.Value, error) {
+	to := reflect.New(toType).Elem()
+	err := c.copyStruct(to, from)
+	return to, err
+}
+
+// copyStruct copies fields of from to existing struct by compiled plan
+func (c *copier) copyStruct(to, from reflect.Value) error {
+	plan, err := c.structPlanOf(from.Type(), to.Type())
+	if err != nil {
+		return err
+	}
+
+	if err := c.checkDepth(); err != nil {
+		return err
+	}
+	if visited, err := c.visit(from, to.Type()); err != nil {
+		return err
+	} else if visited {
+		defer c.leaveVisit()
+	}
+
+	if err := c.runHooks(plan.before, to, from); err != nil {
+		return err
+	}
+
+	for i := range plan.fields {
+		if err := c.ctx.Err(); err != nil {
+			return err
+		}
+		field := &plan.fields[i]
+
+		var fromValue reflect.Value
+		if field.compute != nil {
+			fromValue = field.compute(from)
+		} else {
+			fromValue = fieldByIndex(from, field.from.Index)
+		}
+		if !fromValue.IsValid() {
+			continue
+		}
+
+		if policy := max(c.overwrite, field.policy); policy.Skips(fromValue) {
+			c.logger.Printf("skip(%s:%+v -> %s)", field.name, fromValue.Kind(), field.to.Name)
+			continue
+		}
+
+		toValue := fieldByIndexAlloc(to, field.to.Index)
+		if !toValue.IsValid() || !toValue.CanSet() {
+			continue
+		}
+
+		if field.direct {
+			if fromValue.Type() == toValue.Type() {
+				toValue.Set(fromValue)
+			} else {
+				toValue.Set(fromValue.Convert(toValue.Type()))
+			}
+			continue
+		}
+
+		c.logger.Printf("copyValue(%s:%+v -> %s:%+v)", field.name, fromValue.Kind(), field.to.Name, toValue.Kind())
+		c.enter(fieldSegment(field.name))
+		err := c.copyField(toValue, fromValue, field.stringify)
+		c.leave()
+		if err := c.fail(err); err != nil {
+			return err
+		}
+	}
+
+	for _, fromField := range plan.unmappedFrom {
+		if c.strict&StrictSource != 0 || fromField.tag.Required {
+			if err := c.fail(c.unmappedSourceError(fromField.StructField, to.Type())); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, toField := range plan.unmappedTo {
+		if c.strict&StrictDestination != 0 || toField.tag.Required {
+			if err := c.fail(c.unmappedDestinationError(from.Type(), toField.StructField)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return c.runHooks(plan.after, to, from)
+}
+
+// copyField copies field value, converting number or bool and string each other by `string` tag option
+func (c *copier) copyField(to, from reflect.Value, stringify bool) error {
+	if stringify {
+		v, err := convertByString(from, to.Type())
+		if err != nil {
+			return c.wrapError(err, from, to.Type())
+		}
+		from = v
+	}
+	return c.copyValue(to, from)
+}
+
 func deepFields(reflectType reflect.Type) []reflect.StructField {
 	var fields []reflect.StructField
 
 	if reflectType = indirectType(reflectType); reflectType.Kind() == reflect.Struct {
 		for i := 0; i < reflectType.NumField(); i++ {
 			v := reflectType.Field(i)
-			if v.Anonymous {
-				fields = append(fields, deepFields(v.Type)...)
+			if v.Anonymous || tagOf(v).Inline && indirectType(v.Type).Kind() == reflect.Struct {
+				for _, field := range deepFields(v.Type) {
+					// index from reflectType
+					field.Index = append([]int{i}, field.Index...)
+					fields = append(fields, field)
+				}
 			} else {
 				fields = append(fields, v)
 			}
@@ -231,28 +699,62 @@ func indirectType(reflectType reflect.Type) reflect.Type {
 	return reflectType
 }
 
-func (m *mapper) convert(from reflect.Value, toType reflect.Type) (reflect.Value, error) {
+func (c *copier) convert(from reflect.Value, toType reflect.Type) (reflect.Value, error) {
+	v, err := c.convertValue(from, toType)
+	if err != nil {
+		return v, c.wrapError(err, from, toType)
+	}
+	return v, nil
+}
+
+func (c *copier) convertValue(from reflect.Value, toType reflect.Type) (reflect.Value, error) {
 	if !from.IsValid() {
 		return reflect.Zero(toType), nil
 	}
 
-	if transformer := m.transformerRepository.Get(Target{To: toType, From: from.Type()}); transformer != nil {
-		return transformer(from, toType)
+	if transformer := c.transformerRepository.Get(Target{To: toType, From: from.Type()}); transformer != nil {
+		return transformer(c.ctx, from, toType)
+
+	} else if from.Kind() == reflect.Interface {
+		return c.convert(from.Elem(), toType)
 
-	} else if from.Type().ConvertibleTo(toType) {
+	} else if toType.Kind() == reflect.Interface {
+		return c.convertInterface(from, toType)
+
+	} else if from.Kind() == reflect.Slice && toType.Kind() == reflect.Array {
+		// avoid slice to array conversion, which panics on short slice
+		return c.convertArray(from, toType)
+
+	} else if from.Type().ConvertibleTo(toType) && !copiedByPlan(toType) {
+		// shared as it is, not checked by copying elements
+		if err := c.checkLimits(from); err != nil {
+			return reflect.Zero(toType), err
+		}
 		return from.Convert(toType), nil
 
-	} else if m.canScan(toType) {
-		return m.scan(from, toType)
+	} else if c.canScan(toType) {
+		return c.scan(from, toType)
 
 	} else if from.Kind() == reflect.Ptr {
-		return m.convert(from.Elem(), toType)
+		return c.convert(from.Elem(), toType)
 
 	} else if from.Kind() == reflect.Struct && toType.Kind() == reflect.Struct {
-		return m.convertStruct(from, toType)
+		return c.convertStruct(from, toType)
+
+	} else if (from.Kind() == reflect.Slice || from.Kind() == reflect.Array) && toType.Kind() == reflect.Slice {
+		return c.convertSlice(from, toType)
+
+	} else if from.Kind() == reflect.Array && toType.Kind() == reflect.Array {
+		return c.convertArray(from, toType)
+
+	} else if from.Kind() == reflect.Map && toType.Kind() == reflect.Map {
+		return c.convertMap(from, toType)
+
+	} else if from.Kind() == reflect.Map && toType.Kind() == reflect.Struct && from.Type().Key().Kind() == reflect.String {
+		return c.convertMapToStruct(from, toType)
 
-	} else if from.Kind() == reflect.Slice && toType.Kind() == reflect.Slice {
-		return m.convertSlice(from, toType)
+	} else if from.Kind() == reflect.Struct && toType.Kind() == reflect.Map && toType.Key().Kind() == reflect.String {
+		return c.convertStructToMap(from, toType)
 
 	} else {
 		return reflect.Zero(toType), errors.Errorf("can't convert data %+v -> %+v", from, toType)
@@ -276,6 +778,37 @@ func (m *mapper) scan(from reflect.Value, toType reflect.Type) (reflect.Value, e
 
 var scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
 
+var copiedByPlanTypes sync.Map // map[reflect.Type]bool
+
+// copiedByPlan reports whether t holds structs copied by plan, which are not shared by conversion
+// so that profiles, hooks and tag options apply even if the types are convertible
+func copiedByPlan(t reflect.Type) bool {
+	if v, ok := copiedByPlanTypes.Load(t); ok {
+		return v.(bool)
+	}
+	v := holdsPlannedStruct(t, map[reflect.Type]bool{})
+	copiedByPlanTypes.Store(t, v)
+	return v
+}
+
+func holdsPlannedStruct(t reflect.Type, visited map[reflect.Type]bool) bool {
+	if visited[t] {
+		return false
+	}
+	visited[t] = true
+
+	switch t.Kind() {
+	case reflect.Struct:
+		return hasExportedFields(t) && !reflect.PtrTo(t).Implements(scannerType)
+	case reflect.Slice, reflect.Array:
+		return holdsPlannedStruct(indirectType(t.Elem()), visited)
+	case reflect.Map:
+		return holdsPlannedStruct(indirectType(t.Key()), visited) || holdsPlannedStruct(indirectType(t.Elem()), visited)
+	default:
+		return false
+	}
+}
+
 func forceAddr(v reflect.Value) reflect.Value {
 	if v.Kind() == reflect.Ptr {
 		return v
@@ -289,25 +822,62 @@ func forceAddr(v reflect.Value) reflect.Value {
 	return ptr
 }
 
-func namesOf(field reflect.StructField) []string {
+// namesOf returns names of field by tagNames and field name, or nil if excluded by `-`
+func namesOf(field reflect.StructField, tagNames []string) []string {
+	if tagOf(field).Skip {
+		return nil
+	}
+
 	names := make([]string, 0, 2)
 	for _, tagName := range tagNames {
 		if tag := field.Tag.Get(tagName); tag != "" {
-			name := strings.SplitN(tag, ",", 2)[0]
-			if name != "-" {
-				names = append(names, name)
+			tagged, skip := tagNamesOf(tagName, tag)
+			if skip {
+				return nil
 			}
+			names = append(names, tagged...)
 		}
 	}
 	return append(names, field.Name)
 }
 
-func asNamesToFieldMap(fields []reflect.StructField) map[string]reflect.StructField {
-	m := make(map[string]reflect.StructField)
-	for _, field := range fields {
-		for _, name := range namesOf(field) {
+// FieldMapping is how Mapper matches and copies a struct field, for tools generating equivalent code
+type FieldMapping struct {
+	// Names to match, by tags and field name in order
+	Names []string
+	// Out of strict mode
+	Ignored bool
+	// Policy by `omitempty`, `omitnil` tag options
+	Policy OverwritePolicy
+	// Options of `structmapper` tag
+	Tag Tag
+}
+
+// FieldMappingOf returns FieldMapping of field by tagNames, `structmapper` and `json` if empty
+func FieldMappingOf(field reflect.StructField, tagNames ...string) (FieldMapping, error) {
+	if len(tagNames) == 0 {
+		tagNames = defaultTagNames
+	}
+
+	tag, err := ParseTag(field.Tag.Get("structmapper"))
+	if err != nil {
+		return FieldMapping{}, err
+	}
+	names := namesOf(field, tagNames)
+	return FieldMapping{
+		Names:   names,
+		Ignored: isIgnored(field, names),
+		Policy:  overwritePolicyOf(OverwriteAlways, field),
+		Tag:     tag,
+	}, nil
+}
+
+func asNamesToFieldMap(fields []fieldInfo) map[string]*fieldInfo {
+	m := make(map[string]*fieldInfo)
+	for i := range fields {
+		for _, name := range fields[i].names {
 			if _, found := m[name]; !found {
-				m[name] = field
+				m[name] = &fields[i]
 			}
 		}
 	}
@@ -315,7 +885,9 @@ func asNamesToFieldMap(fields []reflect.StructField) map[string]reflect.StructFi
 }
 
 func (m *mapper) RegisterTransformer(matcher TypeMatcher, transformer Transformer) Mapper {
+	m.mustNotFrozen()
 	m.transformerRepository.Put(matcher, transformer)
+	m.configure(nil)
 	return m
 }
 
@@ -323,43 +895,40 @@ func (m *mapper) RegisterTransformerFunc(matcherFunc TypeMatcherFunc, transforme
 	return m.RegisterTransformer(matcherFunc, transformer)
 }
 
-var tagNames = []string{"structmapper", "json"}
-
-type transformerPair struct {
-	Matcher     TypeMatcher
-	Transformer Transformer
+func (m *mapper) RegisterBidirectional(a, b reflect.Type, forward, backward Transformer) Mapper {
+	return m.
+		RegisterTransformer(Target{From: a, To: b}, forward).
+		RegisterTransformer(Target{From: b, To: a}, backward)
 }
 
-type transformerRepository struct {
-	transformers []transformerPair
-	cache        map[Target]Transformer
-	mutex        sync.Mutex
-}
-
-func newTransformerRepository() *transformerRepository {
-	return &transformerRepository{
-		transformers: nil,
-		cache:        make(map[Target]Transformer),
-	}
+func (m *mapper) RegisterContextTransformer(matcher TypeMatcher, transformer ContextTransformer) Mapper {
+	m.mustNotFrozen()
+	m.transformerRepository.PutContext(matcher, transformer)
+	m.configure(nil)
+	return m
 }
 
-func (r *transformerRepository) Put(matcher TypeMatcher, transformer Transformer) {
-	r.transformers = append(r.transformers, transformerPair{matcher, transformer})
+func (m *mapper) AddTransformer(matcher TypeMatcher, priority int, transformer ContextTransformer) TransformerHandle {
+	m.mustNotFrozen()
+	handle := m.transformerRepository.Add(matcher, transformer, priority)
+	m.configure(nil)
+	return handle
 }
 
-func (r *transformerRepository) Get(target Target) Transformer {
-	r.mutex.Lock()
-	defer r.mutex.Unlock()
-
-	if cached, ok := r.cache[target]; ok {
-		return cached
+func (m *mapper) RemoveTransformer(handle TransformerHandle) Mapper {
+	m.mustNotFrozen()
+	if !m.transformerRepository.Remove(handle) {
+		panic(ErrUnknownTransformer)
 	}
+	m.configure(nil)
+	return m
+}
 
-	for _, pair := range r.transformers {
-		matches := pair.Matcher.Matches(target)
-		if matches {
-			return pair.Transformer
-		}
+func (m *mapper) ReplaceTransformer(handle TransformerHandle, transformer ContextTransformer) Mapper {
+	m.mustNotFrozen()
+	if !m.transformerRepository.Replace(handle, transformer) {
+		panic(ErrUnknownTransformer)
 	}
-	return nil
+	m.configure(nil)
+	return m
 }
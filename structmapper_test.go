@@ -235,6 +235,226 @@ func TestCopy(t *testing.T) {
                                 OptionalString: func() *string { s := "test"; return &s }(),
                         },
                 },
+                {
+                        Name: "map with converted values",
+                        From: &struct {
+                                Times map[string]time.Time `structmapper:"times"`
+                        }{
+                                Times: map[string]time.Time{
+                                        "created": mustTime(time.Parse(time.RFC3339, "2019-07-07T12:34:56Z")),
+                                },
+                        },
+                        EmptyTo: new(struct {
+                                Times map[string]*timestamp.Timestamp `structmapper:"times"`
+                        }),
+                        ExpectedTo: &struct {
+                                Times map[string]*timestamp.Timestamp `structmapper:"times"`
+                        }{
+                                Times: map[string]*timestamp.Timestamp{
+                                        "created": mustTimestampProto(ptypes.TimestampProto(mustTime(time.Parse(time.RFC3339, "2019-07-07T12:34:56Z")))),
+                                },
+                        },
+                },
+                {
+                        Name: "map with converted keys and pointer values",
+                        From: &struct {
+                                Numbers map[int32]*int32 `structmapper:"numbers"`
+                        }{
+                                Numbers: map[int32]*int32{1: Int32(10), 2: nil},
+                        },
+                        EmptyTo: new(struct {
+                                Numbers map[int64]*int64 `structmapper:"numbers"`
+                        }),
+                        ExpectedTo: &struct {
+                                Numbers map[int64]*int64 `structmapper:"numbers"`
+                        }{
+                                Numbers: map[int64]*int64{1: Int64(10), 2: nil},
+                        },
+                },
+                {
+                        Name: "empty map handling",
+                        From: &struct {
+                                Numbers map[string]int32 `structmapper:"numbers"`
+                        }{
+                                Numbers: map[string]int32{},
+                        },
+                        EmptyTo: new(struct {
+                                Numbers map[string]int64 `structmapper:"numbers"`
+                        }),
+                        ExpectedTo: &struct {
+                                Numbers map[string]int64 `structmapper:"numbers"`
+                        }{
+                                Numbers: map[string]int64{},
+                        },
+                },
+                {
+                        Name: "nil map handling",
+                        From: &struct {
+                                Numbers map[string]int32 `structmapper:"numbers"`
+                        }{
+                                Numbers: nil,
+                        },
+                        EmptyTo: new(struct {
+                                Numbers map[string]int64 `structmapper:"numbers"`
+                        }),
+                        ExpectedTo: &struct {
+                                Numbers map[string]int64 `structmapper:"numbers"`
+                        }{
+                                Numbers: nil,
+                        },
+                },
+                {
+                        Name: "map to dto struct",
+                        From: map[string]interface{}{
+                                "id":            "12345",
+                                "name":          "Satoshi Nakamoto",
+                                "age":           47,
+                                "sex":           "Female",
+                                "weight":        12.3,
+                                "alive":         true,
+                                "birth_date":    "1999-11-17",
+                                "num64":         123,
+                                "optional_num":  123,
+                                "OptionalNum64": int64(123),
+                                "numbers":       []interface{}{1, 2, 3},
+                                "times": []interface{}{
+                                        mustTime(time.Parse(time.RFC3339, "2019-07-07T12:34:56Z")),
+                                },
+                                "created_at":  mustTime(time.Parse(time.RFC3339, "2019-07-07T12:34:56Z")),
+                                "modified_at": nil,
+                        },
+                        EmptyTo: new(dto.User),
+                        ExpectedTo: &dto.User{
+                                ID:            "12345",
+                                Name:          "Satoshi Nakamoto",
+                                Age:           47,
+                                Weight:        12.3,
+                                Sex:           dto.SexFemale,
+                                Alive:         true,
+                                BirthDate:     String("1999-11-17"),
+                                Num64:         123,
+                                OptionalNum:   Int32(123),
+                                OptionalNum64: CustonInt64(123),
+                                Numbers:       []int64{1, 2, 3},
+                                Times: []time.Time{
+                                        mustTime(time.Parse(time.RFC3339, "2019-07-07T12:34:56Z")),
+                                },
+                                CreatedAt: mustTime(time.Parse(time.RFC3339, "2019-07-07T12:34:56Z")),
+                        },
+                },
+                {
+                        Name: "dto struct to map",
+                        From: &dto.User{
+                                ID:          "12345",
+                                Sex:         dto.SexFemale,
+                                BirthDate:   String("1999-11-17"),
+                                OptionalNum: Int32(123),
+                                Numbers:     []int64{1, 2, 3},
+                                CreatedAt:   mustTime(time.Parse(time.RFC3339, "2019-07-07T12:34:56Z")),
+                        },
+                        EmptyTo: new(map[string]interface{}),
+                        ExpectedTo: &map[string]interface{}{
+                                "id":             "12345",
+                                "name":           "",
+                                "age":            0,
+                                "sex":            dto.SexFemale,
+                                "weight":         0.0,
+                                "alive":          false,
+                                "birth_date":     "1999-11-17",
+                                "num64":          int64(0),
+                                "optional_num":   int32(123),
+                                "optional_num64": nil,
+                                "numbers":        []interface{}{int64(1), int64(2), int64(3)},
+                                "times":          nil,
+                                "created_at":     mustTime(time.Parse(time.RFC3339, "2019-07-07T12:34:56Z")),
+                                "modified_at":    time.Time{},
+                        },
+                },
+                {
+                        Name: "nested map to nested struct",
+                        From: map[string]interface{}{
+                                "name": "parent",
+                                "children": []interface{}{
+                                        map[string]interface{}{"name": "child", "created_at": "2019-07-07T12:34:56Z"},
+                                },
+                        },
+                        EmptyTo: new(Parent),
+                        ExpectedTo: &Parent{
+                                Name: "parent",
+                                Children: []*Child{
+                                        {Name: "child", CreatedAt: mustTimestampProto(ptypes.TimestampProto(mustTime(time.Parse(time.RFC3339, "2019-07-07T12:34:56Z"))))},
+                                },
+                        },
+                },
+                {
+                        Name: "nested struct to nested map",
+                        From: &Parent{
+                                Name: "parent",
+                                Children: []*Child{
+                                        {Name: "child"},
+                                },
+                        },
+                        EmptyTo: new(map[string]interface{}),
+                        ExpectedTo: &map[string]interface{}{
+                                "name": "parent",
+                                "children": []interface{}{
+                                        map[string]interface{}{"name": "child", "created_at": nil},
+                                },
+                        },
+                },
+                {
+                        Name: "array to slice",
+                        From: &struct {
+                                UUID    [4]byte  `structmapper:"uuid"`
+                                Numbers [3]int32 `structmapper:"numbers"`
+                        }{
+                                UUID:    [4]byte{1, 2, 3, 4},
+                                Numbers: [3]int32{1, 2, 3},
+                        },
+                        EmptyTo: new(struct {
+                                UUID    []byte  `structmapper:"uuid"`
+                                Numbers []int64 `structmapper:"numbers"`
+                        }),
+                        ExpectedTo: &struct {
+                                UUID    []byte  `structmapper:"uuid"`
+                                Numbers []int64 `structmapper:"numbers"`
+                        }{
+                                UUID:    []byte{1, 2, 3, 4},
+                                Numbers: []int64{1, 2, 3},
+                        },
+                },
+                {
+                        Name: "slice to array",
+                        From: &struct {
+                                UUID    []byte                 `structmapper:"uuid"`
+                                Numbers []int32                `structmapper:"numbers"`
+                                Times   []*timestamp.Timestamp `structmapper:"times"`
+                        }{
+                                UUID:    []byte{1, 2},
+                                Numbers: []int32{1, 2, 3},
+                                Times: []*timestamp.Timestamp{
+                                        mustTimestampProto(ptypes.TimestampProto(mustTime(time.Parse(time.RFC3339, "2019-07-07T12:34:56Z")))),
+                                        nil,
+                                },
+                        },
+                        EmptyTo: new(struct {
+                                UUID    [4]byte       `structmapper:"uuid"`
+                                Numbers [3]int64      `structmapper:"numbers"`
+                                Times   [2]*time.Time `structmapper:"times"`
+                        }),
+                        ExpectedTo: &struct {
+                                UUID    [4]byte       `structmapper:"uuid"`
+                                Numbers [3]int64      `structmapper:"numbers"`
+                                Times   [2]*time.Time `structmapper:"times"`
+                        }{
+                                UUID:    [4]byte{1, 2, 0, 0},
+                                Numbers: [3]int64{1, 2, 3},
+                                Times: [2]*time.Time{
+                                        func() *time.Time { t := mustTime(time.Parse(time.RFC3339, "2019-07-07T12:34:56Z")); return &t }(),
+                                        nil,
+                                },
+                        },
+                },
         }
 
         mapper := New().
@@ -252,6 +472,224 @@ func TestCopy(t *testing.T) {
         }
 }
 
+func TestCopyArrayOverflow(t *testing.T) {
+        from := &struct {
+                Numbers []int32 `structmapper:"numbers"`
+        }{
+                Numbers: []int32{1, 2, 3},
+        }
+        to := new(struct {
+                Numbers [2]int64 `structmapper:"numbers"`
+        })
+
+        err := New().From(from).CopyTo(to)
+        assert.EqualError(t, err, "Numbers: can't convert data [1 2 3] -> [2]int64: 3 elements exceed array length 2")
+}
+
+func TestCopyMerge(t *testing.T) {
+        type Profile struct {
+                Bio string `json:"bio"`
+                Age int    `json:"age"`
+        }
+        type Address struct {
+                City string `json:"city"`
+                Zip  string `json:"zip"`
+        }
+        type Entity struct {
+                ID       string   `json:"id"`
+                Name     string   `json:"name"`
+                Profile  *Profile `json:"profile"`
+                Address  Address  `json:"address"`
+                Internal string
+        }
+        type ProfilePatch struct {
+                Bio string `json:"bio"`
+        }
+        type AddressPatch struct {
+                City string `json:"city"`
+        }
+        type EntityPatch struct {
+                Name    string        `json:"name"`
+                Profile *ProfilePatch `json:"profile"`
+                Address AddressPatch  `json:"address"`
+        }
+
+        cases := []struct {
+                Name     string
+                From     interface{}
+                To       *Entity
+                Expected *Entity
+        }{
+                {
+                        Name: "merge struct",
+                        From: &EntityPatch{
+                                Name:    "new name",
+                                Profile: &ProfilePatch{Bio: "new bio"},
+                                Address: AddressPatch{City: "Tokyo"},
+                        },
+                        To: &Entity{
+                                ID:       "12345",
+                                Name:     "old name",
+                                Profile:  &Profile{Bio: "old bio", Age: 47},
+                                Address:  Address{City: "Osaka", Zip: "100-0001"},
+                                Internal: "internal",
+                        },
+                        Expected: &Entity{
+                                ID:       "12345",
+                                Name:     "new name",
+                                Profile:  &Profile{Bio: "new bio", Age: 47},
+                                Address:  Address{City: "Tokyo", Zip: "100-0001"},
+                                Internal: "internal",
+                        },
+                },
+                {
+                        Name: "merge struct into nil pointer target",
+                        From: &EntityPatch{
+                                Profile: &ProfilePatch{Bio: "new bio"},
+                        },
+                        To: &Entity{
+                                ID: "12345",
+                        },
+                        Expected: &Entity{
+                                ID:      "12345",
+                                Profile: &Profile{Bio: "new bio"},
+                        },
+                },
+                {
+                        Name: "merge map",
+                        From: map[string]interface{}{
+                                "name":    "new name",
+                                "address": map[string]interface{}{"city": "Tokyo"},
+                        },
+                        To: &Entity{
+                                ID:       "12345",
+                                Name:     "old name",
+                                Profile:  &Profile{Bio: "old bio", Age: 47},
+                                Address:  Address{City: "Osaka", Zip: "100-0001"},
+                                Internal: "internal",
+                        },
+                        Expected: &Entity{
+                                ID:       "12345",
+                                Name:     "new name",
+                                Profile:  &Profile{Bio: "old bio", Age: 47},
+                                Address:  Address{City: "Tokyo", Zip: "100-0001"},
+                                Internal: "internal",
+                        },
+                },
+        }
+
+        mapper := New()
+        for _, _c := range cases {
+                c := _c
+                t.Run(c.Name, func(t *testing.T) {
+                        profile := c.To.Profile
+                        if assert.NoError(t, mapper.From(c.From).Merge().CopyTo(c.To)) {
+                                assert.Equal(t, c.Expected, c.To)
+                                if profile != nil {
+                                        assert.Same(t, profile, c.To.Profile)
+                                }
+                        }
+                })
+        }
+}
+
+func TestCopyOverwritePolicy(t *testing.T) {
+        type Entity struct {
+                Name    string `json:"name"`
+                Age     int    `json:"age"`
+                Nick    string `json:"nick"`
+                Profile *Child `json:"profile"`
+        }
+        type Patch struct {
+                Name    *string `json:"name"`
+                Age     int     `json:"age"`
+                Nick    string  `json:"nick" structmapper:",omitempty"`
+                Profile *Child  `json:"profile" structmapper:",omitnil"`
+        }
+        existing := func() *Entity {
+                return &Entity{Name: "old name", Age: 47, Nick: "old nick", Profile: &Child{Name: "profile"}}
+        }
+
+        cases := []struct {
+                Name     string
+                Policy   OverwritePolicy
+                From     interface{}
+                Expected *Entity
+        }{
+                {
+                        Name:     "overwrite always",
+                        Policy:   OverwriteAlways,
+                        From:     &Patch{},
+                        Expected: &Entity{Nick: "old nick", Profile: &Child{Name: "profile"}},
+                },
+                {
+                        Name:     "skip nil",
+                        Policy:   SkipNil,
+                        From:     &Patch{},
+                        Expected: &Entity{Name: "old name", Nick: "old nick", Profile: &Child{Name: "profile"}},
+                },
+                {
+                        Name:     "skip zero",
+                        Policy:   SkipZero,
+                        From:     &Patch{},
+                        Expected: &Entity{Name: "old name", Age: 47, Nick: "old nick", Profile: &Child{Name: "profile"}},
+                },
+                {
+                        Name:     "skip zero keeps non-zero",
+                        Policy:   SkipZero,
+                        From:     &Patch{Name: String("new name"), Nick: "new nick"},
+                        Expected: &Entity{Name: "new name", Age: 47, Nick: "new nick", Profile: &Child{Name: "profile"}},
+                },
+                {
+                        Name:     "skip nil of map",
+                        Policy:   SkipNil,
+                        From:     map[string]interface{}{"name": nil, "age": 0},
+                        Expected: &Entity{Name: "old name", Nick: "old nick", Profile: &Child{Name: "profile"}},
+                },
+        }
+
+        mapper := New()
+        for _, _c := range cases {
+                c := _c
+                t.Run(c.Name, func(t *testing.T) {
+                        to := existing()
+                        if assert.NoError(t, mapper.From(c.From).Merge().Overwrite(c.Policy).CopyTo(to)) {
+                                assert.Equal(t, c.Expected, to)
+                        }
+                })
+        }
+}
+
+func TestCopyStructToMapOverwritePolicy(t *testing.T) {
+        type Patch struct {
+                A *string
+                B string
+                C string `structmapper:",omitempty"`
+                D *Child `structmapper:",omitnil"`
+        }
+
+        t.Run("merge into existing map", func(t *testing.T) {
+                to := map[string]interface{}{"A": "keep", "C": "keep", "Extra": 1}
+                if assert.NoError(t, New().From(&Patch{B: "b"}).Merge().Overwrite(SkipNil).CopyTo(&to)) {
+                        assert.Equal(t, map[string]interface{}{"A": "keep", "B": "b", "C": "keep", "Extra": 1}, to)
+                }
+        })
+
+        t.Run("merge into nil map", func(t *testing.T) {
+                var to map[string]interface{}
+                if assert.NoError(t, New().From(&Patch{A: String("a")}).Merge().Overwrite(SkipZero).CopyTo(&to)) {
+                        assert.Equal(t, map[string]interface{}{"A": "a"}, to)
+                }
+        })
+
+        t.Run("tag options of new map", func(t *testing.T) {
+                var to map[string]interface{}
+                if assert.NoError(t, New().From(&Patch{}).CopyTo(&to)) {
+                        assert.Equal(t, map[string]interface{}{"A": nil, "B": ""}, to)
+                }
+        })
+}
+
 func mustTime(t time.Time, err error) time.Time {
         if err != nil {
                 panic(err)
@@ -270,6 +708,10 @@ func Int32(i int32) *int32 {
         return &i
 }
 
+func Int64(i int64) *int64 {
+        return &i
+}
+
 func CustonInt64(i dto.CustomInt64) *dto.CustomInt64 {
         return &i
 }
@@ -285,3 +727,13 @@ type EnumPtrStruct struct {
 type EnumStringStruct struct {
         Sex string `structmapper:"sex"`
 }
+
+type Parent struct {
+        Name     string   `json:"name"`
+        Children []*Child `json:"children"`
+}
+
+type Child struct {
+        Name      string               `json:"name"`
+        CreatedAt *timestamp.Timestamp `json:"created_at"`
+}
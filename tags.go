@@ -0,0 +1,180 @@
+package structmapper
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Tag is parsed `structmapper` tag like `structmapper:"name,omitempty,required"`.
+// Its options are honored on both of source and destination fields, whichever tag names Mapper matches by.
+type Tag struct {
+	// Name to match, or empty
+	Name string
+	// `-`: never mapped
+	Skip bool
+	// `omitempty`: skip zero value source field
+	OmitEmpty bool
+	// `omitnil`: skip nil source field
+	OmitNil bool
+	// `ignore`: out of strict mode
+	Ignore bool
+	// `required`: fail on unmapped field as strict mode does, regardless of StrictMode
+	Required bool
+	// `inline`: match fields of struct field as fields of the parent, as embedded struct
+	Inline bool
+	// `string`: convert number or bool to string and back, as `json:",string"`
+	String bool
+	// `readonly`: read as source field, never written as destination field
+	ReadOnly bool
+	// `writeonly`: written as destination field, never read as source field
+	WriteOnly bool
+}
+
+// ParseTag parses value of `structmapper` tag
+func ParseTag(tag string) (Tag, error) {
+	if tag == "-" {
+		return Tag{Skip: true}, nil
+	}
+
+	name, options, _ := strings.Cut(tag, ",")
+	parsed := Tag{Name: name}
+	for _, option := range strings.Split(options, ",") {
+		switch option {
+		case "":
+		case "omitempty":
+			parsed.OmitEmpty = true
+		case "omitnil":
+			parsed.OmitNil = true
+		case "ignore":
+			parsed.Ignore = true
+		case "required":
+			parsed.Required = true
+		case "inline":
+			parsed.Inline = true
+		case "string":
+			parsed.String = true
+		case "readonly":
+			parsed.ReadOnly = true
+		case "writeonly":
+			parsed.WriteOnly = true
+		default:
+			return Tag{}, errors.Errorf("unknown option %q in tag %q", option, tag)
+		}
+	}
+
+	if parsed.ReadOnly && parsed.WriteOnly {
+		return Tag{}, errors.Errorf("readonly and writeonly are exclusive in tag %q", tag)
+	}
+	return parsed, nil
+}
+
+// tagOf returns parsed `structmapper` tag of field, or zero Tag if invalid
+func tagOf(field reflect.StructField) Tag {
+	tag, _ := ParseTag(field.Tag.Get("structmapper"))
+	return tag
+}
+
+// convertByString converts number or bool to string, or string to number or bool of toType, by `string` tag option.
+// Other values are returned as they are.
+func convertByString(from reflect.Value, toType reflect.Type) (reflect.Value, error) {
+	if from.Kind() == reflect.Ptr && from.IsNil() {
+		return from, nil
+	}
+	from = indirect(from)
+
+	toType = indirectType(toType)
+	if toType.Kind() == reflect.String {
+		switch from.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return reflect.ValueOf(strconv.FormatInt(from.Int(), 10)), nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return reflect.ValueOf(strconv.FormatUint(from.Uint(), 10)), nil
+		case reflect.Float32, reflect.Float64:
+			return reflect.ValueOf(strconv.FormatFloat(from.Float(), 'g', -1, from.Type().Bits())), nil
+		case reflect.Bool:
+			return reflect.ValueOf(strconv.FormatBool(from.Bool())), nil
+		}
+		return from, nil
+	}
+	if from.Kind() != reflect.String {
+		return from, nil
+	}
+
+	to := reflect.New(toType).Elem()
+	switch toType.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(from.String(), 10, toType.Bits())
+		if err != nil {
+			return to, err
+		}
+		to.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(from.String(), 10, toType.Bits())
+		if err != nil {
+			return to, err
+		}
+		to.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(from.String(), toType.Bits())
+		if err != nil {
+			return to, err
+		}
+		to.SetFloat(f)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(from.String())
+		if err != nil {
+			return to, err
+		}
+		to.SetBool(b)
+	default:
+		return from, nil
+	}
+	return to, nil
+}
+
+// Tag names by default
+var defaultTagNames = []string{"structmapper", "json"}
+
+// Parsers of tags not named by the first element
+var tagParsers = map[string]func(tag string) []string{
+	"protobuf": protobufTagNames,
+}
+
+func (m *mapper) TagNames(tagNames ...string) Mapper {
+	m.mustNotFrozen()
+	m.configure(func(config *mapperConfig) {
+		config.tagNames = append([]string(nil), tagNames...)
+	})
+	return m
+}
+
+// tagNamesOf returns names in tag value of tagName, and whether the field is excluded by `-`
+func tagNamesOf(tagName, tag string) ([]string, bool) {
+	if parse, ok := tagParsers[tagName]; ok {
+		return parse(tag), false
+	}
+
+	if tag == "-" {
+		return nil, true
+	}
+	if name := strings.SplitN(tag, ",", 2)[0]; name != "" {
+		return []string{name}, false
+	}
+	return nil, false
+}
+
+// protobufTagNames returns proto field name and JSON name of protobuf tag like `bytes,1,opt,name=birth_date,json=birthDate,proto3`
+func protobufTagNames(tag string) []string {
+	var names []string
+	for _, option := range strings.Split(tag, ",") {
+		if name := strings.TrimPrefix(option, "name="); name != option {
+			names = append(names, name)
+		} else if name := strings.TrimPrefix(option, "json="); name != option {
+			names = append(names, name)
+		}
+	}
+	return names
+}
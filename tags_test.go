@@ -0,0 +1,186 @@
+package structmapper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type UserMessage struct {
+	UserId    string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	BirthDate string `protobuf:"bytes,2,opt,name=birth_date,json=birthDate,proto3" json:"birth_date,omitempty"`
+}
+
+type UserRow struct {
+	ID       string `db:"user_id" json:"id"`
+	Birthday string `db:"birth_date" json:"birthDate"`
+}
+
+func TestTagNames(t *testing.T) {
+	from := &UserMessage{UserId: "u1", BirthDate: "2000-01-01"}
+
+	t.Run("protobuf tag", func(t *testing.T) {
+		to := new(UserRow)
+		if assert.NoError(t, New().TagNames("protobuf", "db").From(from).Strict(StrictBoth).CopyTo(to)) {
+			assert.Equal(t, &UserRow{ID: "u1", Birthday: "2000-01-01"}, to)
+		}
+	})
+
+	t.Run("protobuf json name", func(t *testing.T) {
+		to := new(UserRow)
+		if assert.NoError(t, New().TagNames("protobuf", "json").From(from).CopyTo(to)) {
+			assert.Equal(t, &UserRow{Birthday: "2000-01-01"}, to)
+		}
+	})
+
+	t.Run("json tag disabled", func(t *testing.T) {
+		to := new(UserRow)
+		err := New().
+			TagNames("structmapper").
+			From(&struct {
+				ID string `json:"user_id"`
+			}{ID: "u1"}).
+			CopyTo(to)
+		if assert.NoError(t, err) {
+			assert.Equal(t, "u1", to.ID)
+		}
+
+		to = new(UserRow)
+		err = New().
+			TagNames("structmapper").
+			From(&struct {
+				UserID string `json:"id"`
+			}{UserID: "u1"}).
+			CopyTo(to)
+		if assert.NoError(t, err) {
+			assert.Equal(t, &UserRow{}, to)
+		}
+	})
+
+	t.Run("struct to map by first tag", func(t *testing.T) {
+		to := map[string]interface{}{}
+		if assert.NoError(t, New().TagNames("db").From(&UserRow{ID: "u1"}).CopyTo(&to)) {
+			assert.Equal(t, "u1", to["user_id"])
+		}
+	})
+
+	t.Run("frozen", func(t *testing.T) {
+		assert.PanicsWithValue(t, ErrFrozen, func() {
+			New().Freeze().TagNames("db")
+		})
+	})
+}
+
+func TestParseTag(t *testing.T) {
+	tag, err := ParseTag("name,omitempty,required,string")
+	if assert.NoError(t, err) {
+		assert.Equal(t, Tag{Name: "name", OmitEmpty: true, Required: true, String: true}, tag)
+	}
+
+	tag, err = ParseTag("-")
+	if assert.NoError(t, err) {
+		assert.Equal(t, Tag{Skip: true}, tag)
+	}
+
+	tag, err = ParseTag("-,")
+	if assert.NoError(t, err) {
+		assert.Equal(t, Tag{Name: "-"}, tag)
+	}
+
+	_, err = ParseTag(",omitzero")
+	assert.EqualError(t, err, `unknown option "omitzero" in tag ",omitzero"`)
+
+	_, err = ParseTag(",readonly,writeonly")
+	assert.Error(t, err)
+}
+
+type Audit struct {
+	CreatedBy string
+}
+
+type Document struct {
+	ID       string `structmapper:",readonly"`
+	Title    string `structmapper:",required"`
+	Pages    int    `structmapper:",string"`
+	Audit    Audit  `structmapper:",inline"`
+	Password string `json:"-"`
+	Draft    string `structmapper:",writeonly"`
+}
+
+type DocumentDTO struct {
+	ID        string
+	Title     string
+	Pages     string
+	CreatedBy string
+	Password  string
+	Draft     string
+}
+
+func TestTagOptions(t *testing.T) {
+	t.Run("to struct with options", func(t *testing.T) {
+		to := &Document{ID: "kept"}
+		from := &DocumentDTO{ID: "d1", Title: "Go", Pages: "42", CreatedBy: "taro", Password: "secret", Draft: "draft"}
+
+		if assert.NoError(t, New().From(from).Merge().Strict(StrictDestination).CopyTo(to)) {
+			assert.Equal(t, &Document{ID: "kept", Title: "Go", Pages: 42, Audit: Audit{CreatedBy: "taro"}, Draft: "draft"}, to)
+		}
+	})
+
+	t.Run("from struct with options", func(t *testing.T) {
+		to := new(DocumentDTO)
+		from := &Document{ID: "d1", Title: "Go", Pages: 42, Audit: Audit{CreatedBy: "taro"}, Password: "secret", Draft: "draft"}
+
+		if assert.NoError(t, New().From(from).Strict(StrictSource).CopyTo(to)) {
+			assert.Equal(t, &DocumentDTO{ID: "d1", Title: "Go", Pages: "42", CreatedBy: "taro"}, to)
+		}
+	})
+
+	t.Run("same type", func(t *testing.T) {
+		type Options struct {
+			A string
+			B string `structmapper:"-"`
+			C string `structmapper:",readonly"`
+		}
+
+		to := new(Options)
+		if assert.NoError(t, New().From(&Options{A: "a", B: "b", C: "c"}).CopyTo(to)) {
+			assert.Equal(t, &Options{A: "a"}, to)
+		}
+
+		nested := new(struct{ Options []Options })
+		if assert.NoError(t, New().From(&struct{ Options []Options }{Options: []Options{{A: "a", B: "b", C: "c"}}}).CopyTo(nested)) {
+			assert.Equal(t, []Options{{A: "a"}}, nested.Options)
+		}
+	})
+
+	t.Run("to map", func(t *testing.T) {
+		to := map[string]interface{}{}
+		if assert.NoError(t, New().From(&Document{Pages: 42, Draft: "draft"}).CopyTo(&to)) {
+			assert.Equal(t, "42", to["Pages"])
+			assert.NotContains(t, to, "Password")
+			assert.NotContains(t, to, "Draft")
+		}
+	})
+
+	t.Run("invalid string", func(t *testing.T) {
+		err := New().From(&DocumentDTO{Title: "Go", Pages: "many"}).CopyTo(new(Document))
+		assert.EqualError(t, err, `DocumentDTO.Pages: strconv.ParseInt: parsing "many": invalid syntax`)
+	})
+
+	t.Run("required without strict mode", func(t *testing.T) {
+		err := New().From(&struct{ Pages string }{Pages: "1"}).CopyTo(new(Document))
+		assert.ErrorIs(t, err, ErrUnmappedField)
+
+		err = New().From(map[string]interface{}{"Pages": "1"}).CopyTo(new(Document))
+		assert.ErrorIs(t, err, ErrUnmappedField)
+	})
+
+	t.Run("invalid tag", func(t *testing.T) {
+		err := New().
+			From(&DocumentDTO{}).
+			CopyTo(new(struct {
+				Title string `structmapper:",omitzero"`
+			}))
+		assert.ErrorContains(t, err, `unknown option "omitzero"`)
+	})
+}
@@ -0,0 +1,151 @@
+package gen
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/structmapper/structmapper"
+	"github.com/structmapper/structmapper/test/dto"
+	"github.com/structmapper/structmapper/test/proto"
+)
+
+func newMapper() structmapper.Mapper {
+	return structmapper.New().
+		Install(structmapper.ProtobufModule).
+		Install(structmapper.StringerModule)
+}
+
+func TestMapUserToProto(t *testing.T) {
+	num := int32(42)
+	num64 := dto.CustomInt64(64)
+	birthDate := "2000-01-02"
+	createdAt := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	cases := []struct {
+		name string
+		from *dto.User
+	}{
+		{
+			name: "all fields",
+			from: &dto.User{
+				ID:            "12345",
+				Name:          "山田太郎",
+				Age:           32,
+				Sex:           dto.SexMale,
+				Weight:        60.5,
+				Alive:         true,
+				Num64:         64,
+				OptionalNum:   &num,
+				OptionalNum64: &num64,
+				Numbers:       []int64{1, 2, 3},
+				Times:         []time.Time{createdAt, createdAt.Add(time.Hour)},
+				BirthDate:     &birthDate,
+				CreatedAt:     createdAt,
+				ModifiedAt:    createdAt,
+			},
+		},
+		{
+			name: "nil fields",
+			from: &dto.User{
+				ID:        "12345",
+				CreatedAt: createdAt,
+			},
+		},
+		{
+			name: "empty slices",
+			from: &dto.User{
+				Numbers: []int64{},
+				Times:   []time.Time{},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			expected := new(proto.User)
+			expectedErr := newMapper().From(c.from).CopyTo(expected)
+
+			actual, err := MapUserToProto(c.from)
+			if expectedErr != nil {
+				assert.EqualError(t, err, expectedErr.Error())
+			} else if assert.NoError(t, err) {
+				assert.Equal(t, expected, actual)
+			}
+		})
+	}
+
+	t.Run("nil", func(t *testing.T) {
+		actual, err := MapUserToProto(nil)
+		if assert.NoError(t, err) {
+			assert.Nil(t, actual)
+		}
+	})
+}
+
+func TestMapProtoToUser(t *testing.T) {
+	createdAt := &timestamp.Timestamp{Seconds: 1577934245}
+
+	cases := []struct {
+		name string
+		from *proto.User
+	}{
+		{
+			name: "all fields",
+			from: &proto.User{
+				Id:            "12345",
+				Name:          "山田太郎",
+				Age:           32,
+				Sex:           "Female",
+				Weight:        60.5,
+				Alive:         true,
+				Num64:         64,
+				OptionalNum:   &wrappers.Int64Value{Value: 42},
+				OptionalNum64: &wrappers.Int64Value{Value: 64},
+				Numbers:       []int64{1, 2, 3},
+				Times:         []*timestamp.Timestamp{createdAt, nil},
+				BirthDate:     "2000-01-02",
+				CreatedAt:     createdAt,
+				ModifiedAt:    createdAt,
+			},
+		},
+		{
+			name: "nil fields",
+			from: &proto.User{
+				Id:  "12345",
+				Sex: "male",
+			},
+		},
+		{
+			name: "scan error",
+			from: &proto.User{
+				Sex: "unknown",
+			},
+		},
+		{
+			name: "invalid timestamp",
+			from: &proto.User{
+				Sex:   "male",
+				Times: []*timestamp.Timestamp{createdAt, {Seconds: math.MaxInt64}},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			expected := new(dto.User)
+			expectedErr := newMapper().From(c.from).CopyTo(expected)
+
+			actual, err := MapProtoToUser(c.from)
+			if expectedErr != nil {
+				assert.EqualError(t, err, expectedErr.Error())
+			} else if assert.NoError(t, err) {
+				assert.Equal(t, expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,3 @@
+package gen
+
+//go:generate go run ../../cmd/structmapper-gen -o zz_generated.go -map MapUserToProto=github.com/structmapper/structmapper/test/dto.User,github.com/structmapper/structmapper/test/proto.User -map MapProtoToUser=github.com/structmapper/structmapper/test/proto.User,github.com/structmapper/structmapper/test/dto.User
@@ -0,0 +1,132 @@
+// Code generated by structmapper-gen. DO NOT EDIT.
+
+package gen
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/structmapper/structmapper"
+	"github.com/structmapper/structmapper/test/dto"
+	"github.com/structmapper/structmapper/test/proto"
+)
+
+// MapUserToProto maps dto.User to proto.User
+func MapUserToProto(from *dto.User) (*proto.User, error) {
+	if from == nil {
+		return nil, nil
+	}
+	return mapUserToProto(from, "User")
+}
+
+// MapProtoToUser maps proto.User to dto.User
+func MapProtoToUser(from *proto.User) (*dto.User, error) {
+	if from == nil {
+		return nil, nil
+	}
+	return mapProtoToUser(from, "User")
+}
+
+func mapUserToProto(from *dto.User, path string) (*proto.User, error) {
+	to := new(proto.User)
+	to.Id = from.ID
+	to.Name = from.Name
+	to.Age = int64(from.Age)
+	to.Sex = from.Sex.String()
+	to.Weight = from.Weight
+	to.Alive = from.Alive
+	to.Num64 = from.Num64
+	if from.OptionalNum != nil {
+		to.OptionalNum = &wrappers.Int64Value{Value: int64(*from.OptionalNum)}
+	}
+	if from.OptionalNum64 != nil {
+		to.OptionalNum64 = &wrappers.Int64Value{Value: int64(*from.OptionalNum64)}
+	}
+	to.Numbers = from.Numbers
+	var v1 []*timestamp.Timestamp
+	if from.Times != nil {
+		v1 = make([]*timestamp.Timestamp, 0, len(from.Times))
+		for i2 := range from.Times {
+			ts3, err := ptypes.TimestampProto(from.Times[i2])
+			if err != nil {
+				return nil, &structmapper.MappingError{Path: path + ".Times[" + strconv.Itoa(i2) + "]", Err: err}
+			}
+			v1 = append(v1, ts3)
+		}
+	}
+	to.Times = v1
+	if from.BirthDate != nil {
+		to.BirthDate = *from.BirthDate
+	}
+	ts4, err := ptypes.TimestampProto(from.CreatedAt)
+	if err != nil {
+		return nil, &structmapper.MappingError{Path: path + ".CreatedAt", Err: err}
+	}
+	to.CreatedAt = ts4
+	ts5, err := ptypes.TimestampProto(from.ModifiedAt)
+	if err != nil {
+		return nil, &structmapper.MappingError{Path: path + ".ModifiedAt", Err: err}
+	}
+	to.ModifiedAt = ts5
+	return to, nil
+}
+
+func mapProtoToUser(from *proto.User, path string) (*dto.User, error) {
+	to := new(dto.User)
+	to.ID = from.Id
+	to.Name = from.Name
+	to.Age = int(from.Age)
+	var v1 dto.Sex
+	if err := v1.Scan(from.Sex); err != nil {
+		return nil, &structmapper.MappingError{Path: path + ".Sex", Err: err}
+	}
+	to.Sex = v1
+	to.Weight = from.Weight
+	to.Alive = from.Alive
+	to.Num64 = from.Num64
+	if from.OptionalNum != nil {
+		v2 := int32(from.OptionalNum.Value)
+		to.OptionalNum = &v2
+	}
+	if from.OptionalNum64 != nil {
+		v3 := dto.CustomInt64(from.OptionalNum64.Value)
+		to.OptionalNum64 = &v3
+	}
+	to.Numbers = from.Numbers
+	var v4 []time.Time
+	if from.Times != nil {
+		v4 = make([]time.Time, 0, len(from.Times))
+		for i5 := range from.Times {
+			var v7 time.Time
+			if from.Times[i5] != nil {
+				t6, err := ptypes.Timestamp(from.Times[i5])
+				if err != nil {
+					return nil, &structmapper.MappingError{Path: path + ".Times[" + strconv.Itoa(i5) + "]", Err: err}
+				}
+				v7 = t6
+			}
+			v4 = append(v4, v7)
+		}
+	}
+	to.Times = v4
+	v8 := from.BirthDate
+	to.BirthDate = &v8
+	if from.CreatedAt != nil {
+		t9, err := ptypes.Timestamp(from.CreatedAt)
+		if err != nil {
+			return nil, &structmapper.MappingError{Path: path + ".CreatedAt", Err: err}
+		}
+		to.CreatedAt = t9
+	}
+	if from.ModifiedAt != nil {
+		t10, err := ptypes.Timestamp(from.ModifiedAt)
+		if err != nil {
+			return nil, &structmapper.MappingError{Path: path + ".ModifiedAt", Err: err}
+		}
+		to.ModifiedAt = t10
+	}
+	return to, nil
+}
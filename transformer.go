@@ -1 +1,196 @@
 package structmapper
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+)
+
+// ErrUnknownTransformer is panicked on removing or replacing Transformer not registered by the handle
+var ErrUnknownTransformer = errors.New("unknown transformer handle")
+
+// TransformerHandle identifies Transformer registered by Mapper.AddTransformer, to remove or replace it
+type TransformerHandle struct {
+	id uint64
+}
+
+type transformerPair struct {
+	Matcher     TypeMatcher
+	Transformer ContextTransformer
+	Priority    int
+	handle      TransformerHandle
+}
+
+// exact reports whether matcher is Target, matching the exact pair of types
+func (p transformerPair) exact() bool {
+	_, ok := p.Matcher.(Target)
+	return ok
+}
+
+// transformerRepository resolves ContextTransformer of Target, which Transformer is registered as.
+// Transformers are tried in order of higher priority, exact Target before other matchers, and registration.
+// Transformers and resolved results (including no transformer) are held in an immutable state, replaced on write,
+// so Get is lock-free once a Target is cached. Put drops the cache.
+type transformerRepository struct {
+	state atomic.Pointer[transformerState]
+	mutex sync.Mutex
+}
+
+type transformerState struct {
+	// incremented by Put, Remove and Replace
+	version uint64
+	// last TransformerHandle id
+	lastID       uint64
+	transformers []transformerPair
+	cache        map[Target]ContextTransformer
+}
+
+func newTransformerRepository() *transformerRepository {
+	r := new(transformerRepository)
+	r.state.Store(&transformerState{cache: make(map[Target]ContextTransformer)})
+	return r
+}
+
+// Clone returns repository of same transformers, which is independent of later Put
+func (r *transformerRepository) Clone() *transformerRepository {
+	clone := new(transformerRepository)
+	clone.state.Store(r.state.Load())
+	return clone
+}
+
+func (r *transformerRepository) Put(matcher TypeMatcher, transformer Transformer) {
+	r.PutContext(matcher, func(_ context.Context, from reflect.Value, toType reflect.Type) (reflect.Value, error) {
+		return transformer(from, toType)
+	})
+}
+
+func (r *transformerRepository) PutContext(matcher TypeMatcher, transformer ContextTransformer) {
+	r.Add(matcher, transformer, 0)
+}
+
+// Add registers transformer by priority, and returns its handle.
+// Transformer of exact Target already registered by the same priority is replaced, keeping its handle.
+func (r *transformerRepository) Add(matcher TypeMatcher, transformer ContextTransformer, priority int) TransformerHandle {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	current := r.state.Load()
+	if target, ok := matcher.(Target); ok {
+		for i, pair := range current.transformers {
+			if pair.exact() && pair.Matcher.(Target) == target && pair.Priority == priority {
+				transformers := append([]transformerPair(nil), current.transformers...)
+				transformers[i].Transformer = transformer
+				r.replaceState(current, current.lastID, transformers)
+				return pair.handle
+			}
+		}
+	}
+
+	handle := TransformerHandle{id: current.lastID + 1}
+	transformers := make([]transformerPair, 0, len(current.transformers)+1)
+	transformers = append(transformers, current.transformers...)
+	transformers = append(transformers, transformerPair{matcher, transformer, priority, handle})
+	sort.SliceStable(transformers, func(i, j int) bool {
+		if transformers[i].Priority != transformers[j].Priority {
+			return transformers[i].Priority > transformers[j].Priority
+		}
+		return transformers[i].exact() && !transformers[j].exact()
+	})
+
+	r.replaceState(current, handle.id, transformers)
+	return handle
+}
+
+// Remove deregisters transformer of handle, and reports whether it was registered
+func (r *transformerRepository) Remove(handle TransformerHandle) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	current := r.state.Load()
+	transformers := make([]transformerPair, 0, len(current.transformers))
+	for _, pair := range current.transformers {
+		if pair.handle != handle {
+			transformers = append(transformers, pair)
+		}
+	}
+	if len(transformers) == len(current.transformers) {
+		return false
+	}
+
+	r.replaceState(current, current.lastID, transformers)
+	return true
+}
+
+// Replace replaces transformer of handle keeping its matcher and priority, and reports whether it was registered
+func (r *transformerRepository) Replace(handle TransformerHandle, transformer ContextTransformer) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	current := r.state.Load()
+	transformers := append([]transformerPair(nil), current.transformers...)
+	for i := range transformers {
+		if transformers[i].handle == handle {
+			transformers[i].Transformer = transformer
+			r.replaceState(current, current.lastID, transformers)
+			return true
+		}
+	}
+	return false
+}
+
+// replaceState stores new state of transformers with empty cache, while the mutex is held
+func (r *transformerRepository) replaceState(current *transformerState, lastID uint64, transformers []transformerPair) {
+	r.state.Store(&transformerState{
+		version:      current.version + 1,
+		lastID:       lastID,
+		transformers: transformers,
+		cache:        make(map[Target]ContextTransformer),
+	})
+}
+
+func (r *transformerRepository) Get(target Target) ContextTransformer {
+	state := r.state.Load()
+	if cached, ok := state.cache[target]; ok {
+		return cached
+	}
+
+	var found ContextTransformer
+	for _, pair := range state.transformers {
+		if pair.Matcher.Matches(target) {
+			found = pair.Transformer
+			break
+		}
+	}
+
+	r.store(state, target, found)
+	return found
+}
+
+// store adds resolved transformer to cache by copying it, unless state was replaced
+func (r *transformerRepository) store(state *transformerState, target Target, transformer ContextTransformer) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	current := r.state.Load()
+	if current.version != state.version {
+		// stale
+		return
+	}
+
+	cache := make(map[Target]ContextTransformer, len(current.cache)+1)
+	for k, v := range current.cache {
+		cache[k] = v
+	}
+	cache[target] = transformer
+
+	r.state.Store(&transformerState{
+		version:      current.version,
+		lastID:       current.lastID,
+		transformers: current.transformers,
+		cache:        cache,
+	})
+}
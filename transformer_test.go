@@ -0,0 +1,174 @@
+package structmapper
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransformerRepositoryCache(t *testing.T) {
+	repository := newTransformerRepository()
+
+	matches := 0
+	repository.Put(
+		TypeMatcherFunc(func(target Target) bool {
+			matches++
+			return target.From.Kind() == reflect.Int32 && target.To.Kind() == reflect.String
+		}),
+		func(from reflect.Value, _ reflect.Type) (reflect.Value, error) {
+			return reflect.ValueOf(strconv.FormatInt(from.Int(), 10)), nil
+		},
+	)
+
+	int32ToString := Target{From: reflect.TypeOf(int32(0)), To: reflect.TypeOf("")}
+	int64ToString := Target{From: reflect.TypeOf(int64(0)), To: reflect.TypeOf("")}
+
+	// matched
+	assert.NotNil(t, repository.Get(int32ToString))
+	assert.NotNil(t, repository.Get(int32ToString))
+	// not matched
+	assert.Nil(t, repository.Get(int64ToString))
+	assert.Nil(t, repository.Get(int64ToString))
+	assert.Equal(t, 2, matches, "matcher should be called once per target")
+
+	// invalidated by Put
+	repository.Put(int64ToString, func(from reflect.Value, _ reflect.Type) (reflect.Value, error) {
+		return reflect.ValueOf(strconv.FormatInt(from.Int(), 10)), nil
+	})
+	assert.NotNil(t, repository.Get(int64ToString))
+}
+
+func TestRegisterTransformerAfterCopy(t *testing.T) {
+	type From struct {
+		Age int32 `json:"age"`
+	}
+	type To struct {
+		Age string `json:"age"`
+	}
+
+	mapper := New()
+
+	// int32 -> string by conversion
+	to := new(To)
+	if assert.NoError(t, mapper.From(&From{Age: 65}).CopyTo(to)) {
+		assert.Equal(t, "A", to.Age)
+	}
+
+	mapper.RegisterTransformer(
+		Target{From: reflect.TypeOf(int32(0)), To: reflect.TypeOf("")},
+		func(from reflect.Value, _ reflect.Type) (reflect.Value, error) {
+			return reflect.ValueOf(strconv.FormatInt(from.Int(), 10)), nil
+		},
+	)
+
+	to = new(To)
+	if assert.NoError(t, mapper.From(&From{Age: 65}).CopyTo(to)) {
+		assert.Equal(t, "65", to.Age)
+	}
+}
+
+func TestTransformerPriority(t *testing.T) {
+	type From struct {
+		Age int32
+	}
+	type To struct {
+		Age string
+	}
+
+	int32ToString := Target{From: reflect.TypeOf(int32(0)), To: reflect.TypeOf("")}
+	anyToString := TypeMatcherFunc(func(target Target) bool {
+		return target.To.Kind() == reflect.String
+	})
+	returns := func(s string) ContextTransformer {
+		return func(context.Context, reflect.Value, reflect.Type) (reflect.Value, error) {
+			return reflect.ValueOf(s), nil
+		}
+	}
+	ageOf := func(mapper Mapper) string {
+		to := new(To)
+		if assert.NoError(t, mapper.From(&From{Age: 65}).CopyTo(to)) {
+			return to.Age
+		}
+		return ""
+	}
+
+	t.Run("exact target before predicate registered earlier", func(t *testing.T) {
+		mapper := New().
+			RegisterContextTransformer(anyToString, returns("predicate")).
+			RegisterContextTransformer(int32ToString, returns("exact"))
+		assert.Equal(t, "exact", ageOf(mapper))
+	})
+
+	t.Run("registration order of the same priority", func(t *testing.T) {
+		mapper := New().
+			RegisterContextTransformer(anyToString, returns("first")).
+			RegisterContextTransformer(anyToString, returns("second"))
+		assert.Equal(t, "first", ageOf(mapper))
+	})
+
+	t.Run("exact target registered again", func(t *testing.T) {
+		mapper := New().
+			RegisterContextTransformer(anyToString, returns("predicate")).
+			RegisterContextTransformer(int32ToString, returns("first")).
+			RegisterContextTransformer(int32ToString, returns("second"))
+		assert.Equal(t, "second", ageOf(mapper))
+
+		handle := mapper.AddTransformer(int32ToString, 0, returns("third"))
+		assert.Equal(t, "third", ageOf(mapper))
+		// removes the only registration of the target
+		mapper.RemoveTransformer(handle)
+		assert.Equal(t, "predicate", ageOf(mapper))
+
+		// by another priority
+		mapper.AddTransformer(int32ToString, 0, returns("default"))
+		mapper.AddTransformer(int32ToString, -1, returns("fallback"))
+		assert.Equal(t, "default", ageOf(mapper))
+	})
+
+	t.Run("override module", func(t *testing.T) {
+		mapper := New().Install(ProtobufModule).
+			RegisterTransformer(Target{From: reflect.TypeOf(time.Time{}), To: reflect.TypeOf(timestamp.Timestamp{})}, func(reflect.Value, reflect.Type) (reflect.Value, error) {
+				return reflect.ValueOf(timestamp.Timestamp{Seconds: 1}), nil
+			})
+
+		ts, err := Map[time.Time, *timestamp.Timestamp](mapper, time.Unix(1600000000, 0))
+		if assert.NoError(t, err) {
+			assert.Equal(t, int64(1), ts.Seconds)
+		}
+	})
+
+	t.Run("higher priority first", func(t *testing.T) {
+		mapper := New().RegisterContextTransformer(int32ToString, returns("exact"))
+		mapper.AddTransformer(anyToString, 10, returns("priority"))
+		assert.Equal(t, "priority", ageOf(mapper))
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		mapper := New().RegisterContextTransformer(anyToString, returns("predicate"))
+		handle := mapper.AddTransformer(int32ToString, 0, returns("exact"))
+		assert.Equal(t, "exact", ageOf(mapper))
+
+		mapper.RemoveTransformer(handle)
+		assert.Equal(t, "predicate", ageOf(mapper))
+		assert.PanicsWithValue(t, ErrUnknownTransformer, func() {
+			mapper.RemoveTransformer(handle)
+		})
+	})
+
+	t.Run("replace", func(t *testing.T) {
+		mapper := New()
+		handle := mapper.AddTransformer(int32ToString, 0, returns("old"))
+		assert.Equal(t, "old", ageOf(mapper))
+
+		mapper.ReplaceTransformer(handle, returns("new"))
+		assert.Equal(t, "new", ageOf(mapper))
+		assert.PanicsWithValue(t, ErrUnknownTransformer, func() {
+			mapper.ReplaceTransformer(TransformerHandle{}, returns("unknown"))
+		})
+	})
+}